@@ -0,0 +1,91 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gogost
+
+import (
+	"crypto/cipher"
+	"hash"
+	"time"
+
+	"github.com/hitchpock/gogost/v5/gost28147"
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+	"github.com/hitchpock/gogost/v5/gost341194"
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+// Throughput of the algorithms in MB/s, keyed by their names.
+type BenchmarkResults struct {
+	Ciphers map[string]float64
+	Hashes  map[string]float64
+}
+
+const benchmarkBufSize = 4096
+
+// Measure block ciphers (in ECB mode) and hash functions throughput on
+// the current machine, spending approximately duration on each of them.
+// It has no side effects and is intended for choosing the fastest
+// algorithm at application startup.
+func Benchmark(duration time.Duration) BenchmarkResults {
+	key := make([]byte, 32)
+	return BenchmarkResults{
+		Ciphers: map[string]float64{
+			"GOST 28147-89": benchmarkCipher(
+				gost28147.NewCipher(key, &gost28147.SboxIdGost2814789CryptoProAParamSet), duration,
+			),
+			"Magma":      benchmarkCipher(gost341264.NewCipher(key), duration),
+			"Kuznyechik": benchmarkCipher(gost3412128.NewCipher(key), duration),
+		},
+		Hashes: map[string]float64{
+			"GOST R 34.11-94": benchmarkHash(
+				gost341194.New(&gost28147.SboxIdGostR341194CryptoProParamSet), duration,
+			),
+			"Streebog-256": benchmarkHash(gost34112012256.New(), duration),
+			"Streebog-512": benchmarkHash(gost34112012512.New(), duration),
+		},
+	}
+}
+
+func mbps(n int, elapsed time.Duration) float64 {
+	return float64(n) / elapsed.Seconds() / 1e6
+}
+
+func benchmarkCipher(b cipher.Block, duration time.Duration) float64 {
+	buf := make([]byte, benchmarkBufSize)
+	bs := b.BlockSize()
+	var n int
+	start := time.Now()
+	for n == 0 || time.Since(start) < duration {
+		for i := 0; i+bs <= len(buf); i += bs {
+			b.Encrypt(buf[i:i+bs], buf[i:i+bs])
+		}
+		n += len(buf)
+	}
+	return mbps(n, time.Since(start))
+}
+
+func benchmarkHash(h hash.Hash, duration time.Duration) float64 {
+	buf := make([]byte, benchmarkBufSize)
+	var n int
+	start := time.Now()
+	for n == 0 || time.Since(start) < duration {
+		h.Write(buf)
+		n += len(buf)
+	}
+	h.Sum(nil)
+	return mbps(n, time.Since(start))
+}
@@ -0,0 +1,38 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gogost
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBenchmark(t *testing.T) {
+	res := Benchmark(time.Millisecond)
+	if len(res.Ciphers) != 3 || len(res.Hashes) != 3 {
+		t.FailNow()
+	}
+	for name, v := range res.Ciphers {
+		if v <= 0 {
+			t.Fatal(name, v)
+		}
+	}
+	for name, v := range res.Hashes {
+		if v <= 0 {
+			t.Fatal(name, v)
+		}
+	}
+}
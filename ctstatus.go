@@ -0,0 +1,39 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gogost
+
+// Report which secret dependent operations are free of secret dependent
+// branches and memory accesses, keyed by their names. Status does not
+// depend on the build or runtime configuration: each operation either
+// has constant-time implementation, or not:
+//
+//   - GOST R 34.10 signing inversion: the only inversion during
+//     signing, in gost3410.PrivateKey.SignBlinded, is constant-time.
+//   - GOST R 34.10 scalar multiplication: double-and-add branches on
+//     the scalar bits, SignBlinded only randomizes them.
+//   - Kuznyechik S-box: gost3412128.Cipher uses lookup tables, while
+//     gost3412128.CipherCT scans the whole table.
+//   - Magma and GOST 28147-89 S-boxes use lookup tables.
+func ConstantTimeStatus() map[string]bool {
+	return map[string]bool{
+		"GOST R 34.10 signing inversion":     true,
+		"GOST R 34.10 scalar multiplication": false,
+		"Kuznyechik S-box":                   false,
+		"Kuznyechik CipherCT S-box":          true,
+		"Magma S-box":                        false,
+		"GOST 28147-89 S-box":                false,
+	}
+}
@@ -0,0 +1,37 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gogost
+
+import "testing"
+
+func TestConstantTimeStatus(t *testing.T) {
+	status := ConstantTimeStatus()
+	for _, name := range []string{
+		"GOST R 34.10 signing inversion",
+		"GOST R 34.10 scalar multiplication",
+		"Kuznyechik S-box",
+		"Kuznyechik CipherCT S-box",
+		"Magma S-box",
+		"GOST 28147-89 S-box",
+	} {
+		if _, ok := status[name]; !ok {
+			t.Fatal("missing", name)
+		}
+	}
+	if !status["Kuznyechik CipherCT S-box"] {
+		t.FailNow()
+	}
+}
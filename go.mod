@@ -2,4 +2,7 @@ module github.com/hitchpock/gogost/v5
 
 go 1.20
 
-require golang.org/x/crypto v0.6.0
+require (
+	golang.org/x/crypto v0.6.0
+	golang.org/x/sys v0.5.0
+)
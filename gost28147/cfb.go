@@ -15,64 +15,62 @@
 
 package gost28147
 
+// CFB mode encrypter. Input of any length is accepted: unused bytes of
+// the last gamma block are kept, so the next XORKeyStream call
+// continues the keystream as if data was given at once.
 type CFBEncrypter struct {
-	c  *Cipher
-	iv []byte
+	c    *Cipher
+	iv   []byte
+	used int
 }
 
 func (c *Cipher) NewCFBEncrypter(iv []byte) *CFBEncrypter {
 	if len(iv) != BlockSize {
 		panic("iv length is not equal to blocksize")
 	}
-	encrypter := CFBEncrypter{c: c, iv: make([]byte, BlockSize)}
+	encrypter := CFBEncrypter{c: c, iv: make([]byte, BlockSize), used: BlockSize}
 	copy(encrypter.iv, iv)
 	return &encrypter
 }
 
 func (c *CFBEncrypter) XORKeyStream(dst, src []byte) {
-	var n int
-	i := 0
-MainLoop:
-	for {
-		c.c.Encrypt(c.iv, c.iv)
-		for n = 0; n < BlockSize; n++ {
-			if i*BlockSize+n == len(src) {
-				break MainLoop
-			}
-			c.iv[n] ^= src[i*BlockSize+n]
-			dst[i*BlockSize+n] = c.iv[n]
+	for i := 0; i < len(src); i++ {
+		if c.used == BlockSize {
+			c.c.Encrypt(c.iv, c.iv)
+			c.used = 0
 		}
-		i++
+		c.iv[c.used] ^= src[i]
+		dst[i] = c.iv[c.used]
+		c.used++
 	}
 }
 
+// CFB mode decrypter, the counterpart of CFBEncrypter.
 type CFBDecrypter struct {
-	c  *Cipher
-	iv []byte
+	c    *Cipher
+	iv   []byte
+	used int
 }
 
 func (c *Cipher) NewCFBDecrypter(iv []byte) *CFBDecrypter {
 	if len(iv) != BlockSize {
 		panic("iv length is not equal to blocksize")
 	}
-	decrypter := CFBDecrypter{c: c, iv: make([]byte, BlockSize)}
+	decrypter := CFBDecrypter{c: c, iv: make([]byte, BlockSize), used: BlockSize}
 	copy(decrypter.iv, iv)
 	return &decrypter
 }
 
 func (c *CFBDecrypter) XORKeyStream(dst, src []byte) {
-	var n int
-	i := 0
-MainLoop:
-	for {
-		c.c.Encrypt(c.iv, c.iv)
-		for n = 0; n < BlockSize; n++ {
-			if i*BlockSize+n == len(src) {
-				break MainLoop
-			}
-			dst[i*BlockSize+n] = c.iv[n] ^ src[i*BlockSize+n]
-			c.iv[n] = src[i*BlockSize+n]
+	var b byte
+	for i := 0; i < len(src); i++ {
+		if c.used == BlockSize {
+			c.c.Encrypt(c.iv, c.iv)
+			c.used = 0
 		}
-		i++
+		b = src[i]
+		dst[i] = c.iv[c.used] ^ b
+		c.iv[c.used] = b
+		c.used++
 	}
 }
@@ -79,3 +79,48 @@ func TestCFBInterface(t *testing.T) {
 	var _ cipher.Stream = c.NewCFBEncrypter(iv[:])
 	var _ cipher.Stream = c.NewCFBDecrypter(iv[:])
 }
+
+func TestCFBSplit(t *testing.T) {
+	f := func(key [KeySize]byte, iv [BlockSize]byte, pt []byte, cuts []uint8) bool {
+		c := NewCipher(key[:], SboxDefault)
+		ct := make([]byte, len(pt))
+		c.NewCFBEncrypter(iv[:]).XORKeyStream(ct, pt)
+		fe := c.NewCFBEncrypter(iv[:])
+		fd := c.NewCFBDecrypter(iv[:])
+		ctSplit := make([]byte, len(pt))
+		ptSplit := make([]byte, len(pt))
+		var off int
+		for _, cut := range cuts {
+			end := off + int(cut)%(BlockSize+3)
+			if end > len(pt) {
+				break
+			}
+			fe.XORKeyStream(ctSplit[off:end], pt[off:end])
+			fd.XORKeyStream(ptSplit[off:end], ctSplit[off:end])
+			off = end
+		}
+		fe.XORKeyStream(ctSplit[off:], pt[off:])
+		fd.XORKeyStream(ptSplit[off:], ctSplit[off:])
+		return bytes.Equal(ctSplit, ct) && bytes.Equal(ptSplit, pt)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCFBBlockBoundaries(t *testing.T) {
+	var key [KeySize]byte
+	var iv [BlockSize]byte
+	c := NewCipher(key[:], SboxDefault)
+	pt := make([]byte, 4*BlockSize)
+	ct := make([]byte, len(pt))
+	c.NewCFBEncrypter(iv[:]).XORKeyStream(ct, pt)
+	fe := c.NewCFBEncrypter(iv[:])
+	ctSplit := make([]byte, len(pt))
+	for i := 0; i < len(pt); i += BlockSize {
+		fe.XORKeyStream(ctSplit[i:i+BlockSize], pt[i:i+BlockSize])
+	}
+	if !bytes.Equal(ctSplit, ct) {
+		t.FailNow()
+	}
+}
@@ -17,11 +17,20 @@
 // RFC 5830.
 package gost28147
 
+import "strconv"
+
 const (
 	BlockSize = 8
 	KeySize   = 32
 )
 
+// Error, NewCipher panics with, when key has wrong length.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "gogost/gost28147: invalid key size " + strconv.Itoa(int(k))
+}
+
 // All 28147 operations are going with two 32-bit halves of the whole
 // block. nv is representation of that one half.
 type nv uint32
@@ -52,14 +61,17 @@ var (
 )
 
 type Cipher struct {
-	key  [KeySize]byte
-	sbox *Sbox
-	x    [8]nv
+	key      [KeySize]byte
+	sbox     *Sbox
+	x        [8]nv
+	zeroized bool
 }
 
+// Create new cipher with KeySize-long key. It panics with KeySizeError
+// if key has wrong length.
 func NewCipher(key []byte, sbox *Sbox) *Cipher {
 	if len(key) != KeySize {
-		panic("invalid key size")
+		panic(KeySizeError(len(key)))
 	}
 	c := Cipher{sbox: sbox}
 	copy(c.key[:], key)
@@ -99,7 +111,23 @@ func nvs2block(n1, n2 nv, b []byte) {
 	b[7] = byte((n1 >> 24) & 255)
 }
 
+// Overwrite the key and its schedule with zeros. Cipher is unusable
+// afterwards: any operation with it, including the modes created
+// before, panics.
+func (c *Cipher) Zeroize() {
+	for i := range c.key {
+		c.key[i] = 0
+	}
+	for i := range c.x {
+		c.x[i] = 0
+	}
+	c.zeroized = true
+}
+
 func (c *Cipher) xcrypt(seq Seq, n1, n2 nv) (nv, nv) {
+	if c.zeroized {
+		panic("gogost/gost28147: cipher is zeroized")
+	}
 	for _, i := range seq {
 		n1, n2 = c.sbox.k(n1+c.x[i]).shift11()^n2, n1
 	}
@@ -25,6 +25,20 @@ func TestCipherInterface(t *testing.T) {
 	var _ cipher.Block = NewCipher(make([]byte, KeySize), SboxDefault)
 }
 
+func TestKeySize(t *testing.T) {
+	for _, l := range []int{0, KeySize - 1, KeySize + 1} {
+		func() {
+			defer func() {
+				if err, ok := recover().(KeySizeError); !ok || int(err) != l {
+					t.Fatal(l)
+				}
+			}()
+			NewCipher(make([]byte, l), SboxDefault)
+		}()
+	}
+	NewCipher(make([]byte, KeySize), SboxDefault)
+}
+
 func BenchmarkCipher(b *testing.B) {
 	var key [KeySize]byte
 	rand.Read(key[:])
@@ -37,3 +51,29 @@ func BenchmarkCipher(b *testing.B) {
 		c.Encrypt(dst, src)
 	}
 }
+
+func TestZeroize(t *testing.T) {
+	key := make([]byte, KeySize)
+	rand.Read(key)
+	c := NewCipher(key, SboxDefault)
+	ctr := c.NewCTR(make([]byte, BlockSize))
+	c.Zeroize()
+	if c.key != [KeySize]byte{} || c.x != [8]nv{} {
+		t.Fatal("key is left")
+	}
+	buf := make([]byte, BlockSize)
+	for _, f := range []func(){
+		func() { c.Encrypt(buf, buf) },
+		func() { c.Decrypt(buf, buf) },
+		func() { ctr.XORKeyStream(buf, buf) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic after Zeroize")
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -15,10 +15,19 @@
 
 package gost28147
 
+import (
+	"errors"
+	"io"
+)
+
 type CTR struct {
-	c  *Cipher
-	n1 nv
-	n2 nv
+	c    *Cipher
+	n1   nv
+	n2   nv
+	n10  nv
+	n20  nv
+	skip int
+	pos  int64
 }
 
 func (c *Cipher) NewCTR(iv []byte) *CTR {
@@ -27,10 +36,110 @@ func (c *Cipher) NewCTR(iv []byte) *CTR {
 	}
 	n1, n2 := block2nvs(iv)
 	n2, n1 = c.xcrypt(SeqEncrypt, n1, n2)
-	return &CTR{c, n1, n2}
+	return &CTR{c: c, n1: n1, n2: n2, n10: n1, n20: n2}
+}
+
+func (c *CTR) inc() {
+	c.n1 += 0x01010101 // C2
+	c.n2 += 0x01010104 // C1
+	if c.n2 >= 1<<32-1 {
+		c.n2 -= 1<<32 - 1
+	}
+}
+
+func (c *CTR) next() (nv, nv) {
+	c.inc()
+	return c.c.xcrypt(SeqEncrypt, c.n1, c.n2)
+}
+
+// Inverse of C1/4 modulo 2^30
+const c1QuarterInv = 0x24dbcfc1
+
+// Get the counter after k increments of (n1, n2), computed directly.
+// inc adds C1 to n2 modulo 2^32, replacing resulting 2^32-1 with zero.
+// C1 is multiple of 4, so n2 modulo 4 is changed only by that
+// replacement, and n2 can reach 2^32-1 at most once: if it is 3
+// modulo 4, after j increments, where j*C1 = 2^32-1-n2 modulo 2^32.
+func ctrAdvance(n1, n2 nv, k uint64) (nv, nv) {
+	n1k := n1 + nv(k)*0x01010101
+	n2k := n2 + nv(k)*0x01010104
+	if n2&3 == 3 {
+		j := uint64(((^n2)>>2)*c1QuarterInv) & (1<<30 - 1)
+		if j == 0 {
+			j = 1 << 30
+		}
+		if j <= k {
+			n2k++
+		}
+	}
+	return n1k, n2k
+}
+
+// Position the stream at the offset byte, as if the keystream was
+// produced by single XORKeyStream call from the beginning. Counter is
+// calculated directly, without encryption. As XORKeyStream calls do not
+// continue each other's keystream blocks, each of them moves current
+// position past the block containing the byte following processed ones.
+// io.SeekEnd whence is not supported, as keystream has no end.
+func (c *CTR) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += c.pos
+	default:
+		return 0, errors.New("gogost/gost28147: only io.SeekStart and io.SeekCurrent are supported")
+	}
+	if offset < 0 {
+		return 0, errors.New("gogost/gost28147: negative offset")
+	}
+	c.n1, c.n2 = ctrAdvance(c.n10, c.n20, uint64(offset/BlockSize))
+	c.skip = int(offset % BlockSize)
+	c.pos = offset
+	return offset, nil
 }
 
 func (c *CTR) XORKeyStream(dst, src []byte) {
+	if len(src) > 0 {
+		c.pos = ((c.pos+int64(len(src)))/BlockSize + 1) * BlockSize
+	}
+	if c.skip > 0 && len(src) > 0 {
+		var block [BlockSize]byte
+		n1t, n2t := c.next()
+		nvs2block(n1t, n2t, block[:])
+		n := BlockSize - c.skip
+		if len(src) < n {
+			n = len(src)
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ block[c.skip+i]
+		}
+		if len(src) == n && c.skip+n == BlockSize {
+			c.inc()
+		}
+		c.skip = 0
+		dst, src = dst[n:], src[n:]
+		if len(src) == 0 {
+			return
+		}
+	}
+	if len(src) > BlockSize {
+		c.xorKeyStream(dst, src)
+		return
+	}
+	// Single block fast path, without allocations and loops overhead
+	var block [BlockSize]byte
+	n1t, n2t := c.next()
+	nvs2block(n1t, n2t, block[:])
+	for n := 0; n < len(src); n++ {
+		dst[n] = src[n] ^ block[n]
+	}
+	if len(src) == BlockSize {
+		// General path skips the counter after the full final block
+		c.inc()
+	}
+}
+
+func (c *CTR) xorKeyStream(dst, src []byte) {
 	var n1t nv
 	var n2t nv
 	block := make([]byte, BlockSize)
@@ -38,12 +147,7 @@ func (c *CTR) XORKeyStream(dst, src []byte) {
 	var n int
 MainLoop:
 	for {
-		c.n1 += 0x01010101 // C2
-		c.n2 += 0x01010104 // C1
-		if c.n2 >= 1<<32-1 {
-			c.n2 -= 1<<32 - 1
-		}
-		n1t, n2t = c.c.xcrypt(SeqEncrypt, c.n1, c.n2)
+		n1t, n2t = c.next()
 		nvs2block(n1t, n2t, block)
 		for n = 0; n < BlockSize; n++ {
 			if i*BlockSize+n == len(src) {
@@ -19,6 +19,7 @@ import (
 	"bytes"
 	"crypto/cipher"
 	"crypto/rand"
+	"io"
 	"testing"
 	"testing/quick"
 )
@@ -172,6 +173,110 @@ func TestCTRRandom(t *testing.T) {
 		t.Error(err)
 	}
 }
+func TestCTRSingleBlock(t *testing.T) {
+	f := func(key [KeySize]byte, iv [BlockSize]byte, pt [BlockSize]byte) bool {
+		c := NewCipher(key[:], SboxDefault)
+		fast := c.NewCTR(iv[:])
+		generic := c.NewCTR(iv[:])
+		got := make([]byte, BlockSize)
+		expected := make([]byte, BlockSize)
+		for l := 0; l <= BlockSize; l++ {
+			fast.XORKeyStream(got[:l], pt[:l])
+			generic.xorKeyStream(expected[:l], pt[:l])
+			if !bytes.Equal(got[:l], expected[:l]) {
+				return false
+			}
+		}
+		return fast.n1 == generic.n1 && fast.n2 == generic.n2
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCTRSeek(t *testing.T) {
+	key := make([]byte, KeySize)
+	iv := make([]byte, BlockSize)
+	rand.Read(key)
+	rand.Read(iv)
+	c := NewCipher(key, SboxDefault)
+	pt := make([]byte, 1000)
+	rand.Read(pt)
+	full := make([]byte, len(pt))
+	c.NewCTR(iv).XORKeyStream(full, pt)
+	ctr := c.NewCTR(iv)
+	if _, err := ctr.Seek(1, io.SeekEnd); err == nil {
+		t.FailNow()
+	}
+	if _, err := ctr.Seek(-1, io.SeekStart); err == nil {
+		t.FailNow()
+	}
+	for _, offset := range []int{0, 1, 7, 8, 9, 15, 16, 123, 504, 999} {
+		for _, l := range []int{1, 7, 8, 9, 17, len(pt) - offset} {
+			if offset+l > len(pt) {
+				continue
+			}
+			if _, err := ctr.Seek(int64(offset), io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			got := make([]byte, l)
+			ctr.XORKeyStream(got, pt[offset:offset+l])
+			if !bytes.Equal(got, full[offset:offset+l]) {
+				t.Fatal(offset, l)
+			}
+		}
+	}
+	if _, err := ctr.Seek(8, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 10)
+	ctr.XORKeyStream(got[:3], pt[8:11])
+	// The rest of the block is skipped
+	if pos, err := ctr.Seek(0, io.SeekCurrent); err != nil || pos != 16 {
+		t.Fatal(pos, err)
+	}
+	ctr.XORKeyStream(got[3:], pt[16:23])
+	if !bytes.Equal(got[:3], full[8:11]) || !bytes.Equal(got[3:], full[16:23]) {
+		t.FailNow()
+	}
+	if _, err := ctr.Seek(-30, io.SeekCurrent); err == nil {
+		t.FailNow()
+	}
+	if _, err := ctr.Seek(-24, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	ctr.XORKeyStream(got, pt[:10])
+	if !bytes.Equal(got, full[:10]) {
+		t.FailNow()
+	}
+}
+
+func TestCTRAdvance(t *testing.T) {
+	ctr := CTR{}
+	check := func(n1, n2 nv, k int) bool {
+		ctr.n1, ctr.n2 = n1, n2
+		for i := 0; i < k; i++ {
+			ctr.inc()
+			gotN1, gotN2 := ctrAdvance(n1, n2, uint64(i+1))
+			if gotN1 != ctr.n1 || gotN2 != ctr.n2 {
+				return false
+			}
+		}
+		return true
+	}
+	f := func(n1, n2 uint32, j uint8) bool {
+		// n2 reaching 2^32-1 after j increments
+		hit := nv(1<<32-1) - nv(j)*0x01010104
+		return check(nv(n1), nv(n2), 300) && check(nv(n1), hit, 2*int(j)+1)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	if !check(0, 1<<32-1, 300) {
+		t.FailNow()
+	}
+}
+
 func TestCTRInterface(t *testing.T) {
 	var key [KeySize]byte
 	var iv [8]byte
@@ -194,3 +299,19 @@ func BenchmarkCTR(b *testing.B) {
 		ctr.XORKeyStream(dst, src)
 	}
 }
+
+func BenchmarkCTRSingleBlockGeneric(b *testing.B) {
+	key := make([]byte, KeySize)
+	iv := make([]byte, BlockSize)
+	rand.Read(key)
+	rand.Read(iv)
+	dst := make([]byte, BlockSize)
+	src := make([]byte, BlockSize)
+	rand.Read(src)
+	c := NewCipher(key, SboxDefault)
+	ctr := c.NewCTR(iv)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctr.xorKeyStream(dst, src)
+	}
+}
@@ -58,7 +58,7 @@ func (c *Cipher) NewMAC(size int, iv []byte) (*MAC, error) {
 
 func (m *MAC) Reset() {
 	copy(m.prev, m.iv)
-	m.buf = nil
+	m.buf = m.buf[:0]
 }
 
 func (m *MAC) BlockSize() int {
@@ -87,16 +87,14 @@ func (m *MAC) Sum(b []byte) []byte {
 	if len(m.buf) == 0 {
 		return append(b, m.prev[0:m.size]...)
 	}
-	buf := m.buf
-	var i int
-	for i = 0; i < BlockSize-len(m.buf); i++ {
-		buf = append(buf, byte(0))
-	}
-	for i = 0; i < BlockSize; i++ {
+	// Padded last block is processed in a copy, keeping the state intact
+	var buf [BlockSize]byte
+	copy(buf[:], m.buf)
+	for i := 0; i < BlockSize; i++ {
 		buf[i] ^= m.prev[i]
 	}
-	m.n1, m.n2 = block2nvs(buf)
-	m.n1, m.n2 = m.c.xcrypt(SeqMAC, m.n1, m.n2)
-	nvs2block(m.n2, m.n1, buf)
+	n1, n2 := block2nvs(buf[:])
+	n1, n2 = m.c.xcrypt(SeqMAC, n1, n2)
+	nvs2block(n2, n1, buf[:])
 	return append(b, buf[0:m.size]...)
 }
@@ -126,3 +126,37 @@ func BenchmarkMAC(b *testing.B) {
 		mac.Sum(nil)
 	}
 }
+
+func TestMACReset(t *testing.T) {
+	var key [KeySize]byte
+	var iv [BlockSize]byte
+	rand.Read(key[:])
+	rand.Read(iv[:])
+	c := NewCipher(key[:], SboxDefault)
+	m, err := c.NewMAC(8, iv[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Write([]byte("first message to forget"))
+	m.Sum(nil)
+	m.Reset()
+	data := []byte("second message")
+	m.Write(data)
+	tag := m.Sum(nil)
+	if !bytes.Equal(m.Sum(nil), tag) {
+		t.Fatal("Sum altered the state")
+	}
+	fresh, err := c.NewMAC(8, iv[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	fresh.Write(data)
+	if !bytes.Equal(fresh.Sum(nil), tag) {
+		t.FailNow()
+	}
+	m.Write(data)
+	fresh.Write(data)
+	if !bytes.Equal(m.Sum(nil), fresh.Sum(nil)) {
+		t.Fatal("Sum altered the state")
+	}
+}
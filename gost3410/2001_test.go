@@ -13,6 +13,9 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
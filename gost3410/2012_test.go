@@ -13,11 +13,15 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
 	"bytes"
 	"crypto/rand"
+	"encoding/asn1"
 	"encoding/hex"
 	"math/big"
 	"testing"
@@ -68,6 +72,13 @@ func TestStdVector1(t *testing.T) {
 	if !bytes.Equal(sign, append(s, r...)) {
 		t.FailNow()
 	}
+	sign, err = prv.SignWithK(dgst, bytes2big(rnd))
+	if err != nil {
+		t.FailNow()
+	}
+	if !bytes.Equal(sign, append(s, r...)) {
+		t.FailNow()
+	}
 }
 
 // Test vector from GOST R 34.10-2012 appendix
@@ -193,6 +204,33 @@ func TestStdVector2(t *testing.T) {
 	if !bytes.Equal(sign, append(s, r...)) {
 		t.FailNow()
 	}
+	for _, reg := range []*Curve{
+		CurveByName("id-tc26-gost-3410-12-512-paramSetTest"),
+		CurveByName("id-tc26-gost-3410-2012-512-paramSetTest"),
+		CurveByOID(asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 0}),
+	} {
+		if reg == nil || !reg.Equal(c) {
+			t.Fatal("paramSetTest is not registered")
+		}
+		prv, err = NewPrivateKey(reg, prvRaw)
+		if err != nil {
+			t.FailNow()
+		}
+		sign, err = prv.SignDigest(dgst, bytes.NewBuffer(rnd))
+		if err != nil {
+			t.FailNow()
+		}
+		if !bytes.Equal(sign, append(s, r...)) {
+			t.FailNow()
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.FailNow()
+		}
+		if valid, err := pub.VerifyDigest(dgst, sign); err != nil || !valid {
+			t.FailNow()
+		}
+	}
 }
 
 func TestGCL3Vectors(t *testing.T) {
@@ -617,3 +655,254 @@ func BenchmarkVerify2012(b *testing.B) {
 		pub.VerifyDigest(digest, sign)
 	}
 }
+
+func TestSignWithKRange(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	for _, k := range []*big.Int{big.NewInt(0), big.NewInt(-1), c.Q} {
+		if _, err = prv.SignWithK(digest, k); err == nil {
+			t.FailNow()
+		}
+	}
+	sign1, err := prv.SignWithK(digest, big.NewInt(12345))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign2, _ := prv.SignWithK(digest, big.NewInt(12345))
+	if !bytes.Equal(sign1, sign2) {
+		t.FailNow()
+	}
+}
+
+func TestSignRetriesZeroRS(t *testing.T) {
+	// Toy prime order curve, having (0, 425) point with zero r
+	c, err := NewCurve(
+		big.NewInt(1009), big.NewInt(1013),
+		big.NewInt(1), big.NewInt(14),
+		big.NewInt(1), big.NewInt(4),
+		nil, nil, nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kZeroR := big.NewInt(1)
+	for ; kZeroR.Cmp(c.Q) < 0; kZeroR.Add(kZeroR, bigInt1) {
+		x, _, err := c.ScalarBaseMult(kZeroR)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Sign() == 0 {
+			break
+		}
+	}
+	if kZeroR.Cmp(c.Q) == 0 {
+		t.FailNow()
+	}
+	digest := pad([]byte{0x12, 0x34}, c.PointSize())
+	kGood := big.NewInt(7)
+	prv := &PrivateKey{C: c, Key: big.NewInt(123)}
+	expected, err := prv.SignWithK(digest, kGood)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prv.SignWithK(digest, kZeroR); err == nil {
+		t.FailNow()
+	}
+	rnd := append(pad(kZeroR.Bytes(), c.PointSize()), pad(kGood.Bytes(), c.PointSize())...)
+	sign, err := prv.SignDigest(digest, bytes.NewReader(rnd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sign, expected) {
+		t.FailNow()
+	}
+
+	// s = r*d + k*e is zero for d = -k*e/r
+	kZeroS := big.NewInt(5)
+	r, err := c.NonceCommitment(kZeroS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := c.DigestToScalar(digest)
+	d := big.NewInt(0).Mul(kZeroS, e)
+	d.Neg(d)
+	d.Mul(d, big.NewInt(0).ModInverse(r, c.Q))
+	d.Mod(d, c.Q)
+	prv = &PrivateKey{C: c, Key: d}
+	if _, err = prv.SignWithK(digest, kZeroS); err == nil {
+		t.FailNow()
+	}
+	expected, err = prv.SignWithK(digest, kGood)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rnd = append(pad(kZeroS.Bytes(), c.PointSize()), pad(kGood.Bytes(), c.PointSize())...)
+	sign, err = prv.SignDigest(digest, bytes.NewReader(rnd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sign, expected) {
+		t.FailNow()
+	}
+}
+
+func TestNonceCommitment(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []*big.Int{big.NewInt(0), big.NewInt(-1), c.Q} {
+			if _, err = c.NonceCommitment(k); err == nil {
+				t.FailNow()
+			}
+		}
+		kRaw := make([]byte, c.PointSize())
+		rand.Read(kRaw)
+		k := bytes2big(kRaw)
+		k.Mod(k, c.Q)
+		r, err := c.NonceCommitment(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.PointSize())
+		rand.Read(digest)
+		sign, err := prv.SignWithK(digest, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signR, _, err := SignatureToRS(c, sign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Cmp(signR) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
+// GOST R 34.10-2012 appendix vectors give e as an integer, that
+// SignDigest takes big-endian encoded on both curve sizes. Little-endian
+// Streebog output has to be reversed, like PrivateKeyReverseDigest does.
+func TestDigestEndianness(t *testing.T) {
+	for _, v := range []struct {
+		c                *Curve
+		prv, e, k, r, sg string
+	}{
+		{
+			c:   CurveIdGostR34102001TestParamSet(),
+			prv: "7a929ade789bb9be10ed359dd39a72c11b60961f49397eee1d19ce9891ec3b28",
+			e:   "2dfbc1b372d89a1188c09c52e0eec61fce52032ab1022e8e67ece6672b043ee5",
+			k:   "77105c9b20bcd3122823c8cf6fcc7b956de33814e95b7fe64fed924594dceab3",
+			r:   "41aa28d2f1ab148280cd9ed56feda41974053554a42767b83ad043fd39dc0493",
+			sg:  "01456c64ba4642a1653c235a98a60249bcd6d3f746b631df928014f6c5bf9c40",
+		},
+		{
+			c: CurveByName("id-tc26-gost-3410-12-512-paramSetTest"),
+			prv: "0ba6048aadae241ba40936d47756d7c93091a0e8514669700ee7508e508b1020" +
+				"72e8123b2200a0563322dad2827e2714a2636b7bfd18aadfc62967821fa18dd4",
+			e: "3754f3cfacc9e0615c4f4a7c4d8dab531b09b6f9c170c533a71d147035b0c591" +
+				"7184ee536593f4414339976c647c5d5a407adedb1d560c4fc6777d2972075b8c",
+			k: "0359e7f4b1410feacc570456c6801496946312120b39d019d455986e364f3658" +
+				"86748ed7a44b3e794434006011842286212273a6d14cf70ea3af71bb1ae679f1",
+			r: "2f86fa60a081091a23dd795e1e3c689ee512a3c82ee0dcc2643c78eea8fcacd3" +
+				"5492558486b20f1c9ec197c90699850260c93bcbcd9c5c3317e19344e173ae36",
+			sg: "1081b394696ffe8e6585e7a9362d26b6325f56778aadbc081c0bfbe933d52ff5" +
+				"823ce288e8c4f362526080df7f70ce406a6eeb1f56919cb92a9853bde73e5b4a",
+		},
+	} {
+		c := v.c
+		prvRaw := mustHex(v.prv)
+		reverse(prvRaw)
+		prv, err := NewPrivateKey(c, prvRaw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := mustHex(v.e)
+		if c.DigestToScalar(digest).Cmp(bytes2big(digest)) != 0 {
+			t.Fatal(c.Name, "digest is not big-endian")
+		}
+		expected := append(mustHex(v.sg), mustHex(v.r)...)
+		sign, err := prv.SignDigest(digest, bytes.NewReader(mustHex(v.k)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sign, expected) {
+			t.Fatal(c.Name, "SignDigest differs from the vector")
+		}
+		digestLE := append([]byte{}, digest...)
+		reverse(digestLE)
+		sign, err = (&PrivateKeyReverseDigest{prv}).Sign(
+			bytes.NewReader(mustHex(v.k)), digestLE, nil,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sign, expected) {
+			t.Fatal(c.Name, "reversed digest signature differs from the vector")
+		}
+		if valid, err := pub.VerifyDigestReversed(digestLE, expected); err != nil || !valid {
+			t.Fatal(c.Name, "reversed digest is not verified")
+		}
+		if valid, _ := pub.VerifyDigest(digestLE, expected); valid {
+			t.Fatal(c.Name, "little-endian digest is verified")
+		}
+	}
+	// Streebog-based signers reverse the digest the same way
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := make([]byte, 100)
+		rand.Read(msg)
+		h := curveHash(c)
+		h.Write(msg)
+		digestLE := h.Sum(nil)
+		if len(digestLE) != c.PointSize() {
+			t.Fatal(c.Name)
+		}
+		digest := append([]byte{}, digestLE...)
+		reverse(digest)
+		e := big.NewInt(0).SetBytes(digest)
+		if c.DigestToScalar(digest).Cmp(e.Mod(e, c.Q)) != 0 {
+			t.Fatal(c.Name)
+		}
+		kRaw := make([]byte, c.PointSize())
+		rand.Read(kRaw)
+		sign, err := prv.SignDigest(digest, bytes.NewReader(kRaw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		signReversed, err := (&PrivateKeyReverseDigest{prv}).Sign(bytes.NewReader(kRaw), digestLE, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sign, signReversed) {
+			t.Fatal(c.Name)
+		}
+		signer := prv.NewSigner()
+		signer.Write(msg)
+		signStream, err := signer.Sign(bytes.NewReader(kRaw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sign, signStream) {
+			t.Fatal(c.Name)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/mgm"
+)
+
+var (
+	// Backup is malformed or truncated
+	ErrBackupCorrupted = errors.New("gogost/gost3410: corrupted backup")
+
+	// MGM authentication failed: either the passphrase is wrong, or the
+	// encrypted data is tampered, that MGM can not distinguish
+	ErrBackupAuth = errors.New("gogost/gost3410: wrong backup passphrase or tampered data")
+
+	// Decrypted key does not match its checksum
+	ErrBackupChecksum = errors.New("gogost/gost3410: backup checksum mismatch")
+)
+
+// Key backup format:
+//
+//	Backup ::= SEQUENCE {
+//	    version    INTEGER, -- 0
+//	    salt       OCTET STRING,
+//	    iterations INTEGER,
+//	    nonce      OCTET STRING,
+//	    encrypted  OCTET STRING }
+//
+// encrypted is Kuznyechik-MGM encryption of
+//
+//	BackupPayload ::= SEQUENCE {
+//	    key      BackupKey,
+//	    checksum OCTET STRING -- Streebog-512 of DER-encoded key
+//	}
+//	BackupKey ::= SEQUENCE {
+//	    curve OBJECT IDENTIFIER,
+//	    key   OCTET STRING -- raw little-endian private key
+//	}
+//
+// under PBKDF2-HMAC-Streebog-512 derived key, like in PKCS #8.
+type backup struct {
+	Version    int
+	Salt       []byte
+	Iterations int
+	Nonce      []byte
+	Encrypted  []byte
+}
+
+type backupPayload struct {
+	Key      asn1.RawValue
+	Checksum []byte
+}
+
+type backupKey struct {
+	Curve asn1.ObjectIdentifier
+	Key   []byte
+}
+
+func backupChecksum(key []byte) []byte {
+	h := gost34112012512.New()
+	h.Write(key)
+	return h.Sum(nil)
+}
+
+func backupAEAD(passphrase, salt []byte, iterations int) (cipher.AEAD, error) {
+	return mgm.NewMGM(
+		gost3412128.NewCipher(pkcs8KEK(passphrase, salt, iterations)),
+		gost3412128.BlockSize,
+	)
+}
+
+// Encrypt the payload with the passphrase into the backup.
+func sealBackup(payload, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gost3412128.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	nonce[0] &= 0x7F
+	aead, err := backupAEAD(passphrase, salt, PKCS8Iterations)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(backup{
+		Salt:       salt,
+		Iterations: PKCS8Iterations,
+		Nonce:      nonce,
+		Encrypted:  aead.Seal(nil, nonce, payload, nil),
+	})
+}
+
+// Serialize the curve's OID and the private key together with their
+// Streebog-512 checksum and encrypt them with Kuznyechik-MGM under
+// PBKDF2-HMAC-Streebog-512 passphrase-derived key. Curve must have OID.
+func (prv *PrivateKey) ExportBackup(passphrase []byte) ([]byte, error) {
+	if len(prv.C.OID) == 0 {
+		return nil, errors.New("gogost/gost3410.PrivateKey.ExportBackup: curve has no OID")
+	}
+	raw := prv.Raw()
+	defer zeroizeBytes(raw)
+	key, err := asn1.Marshal(backupKey{Curve: prv.C.OID, Key: raw})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ExportBackup: %w", err)
+	}
+	defer zeroizeBytes(key)
+	payload, err := asn1.Marshal(backupPayload{
+		Key:      asn1.RawValue{FullBytes: key},
+		Checksum: backupChecksum(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ExportBackup: %w", err)
+	}
+	defer zeroizeBytes(payload)
+	data, err := sealBackup(payload, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ExportBackup: %w", err)
+	}
+	return data, nil
+}
+
+// Decrypt and verify the backup made with ExportBackup. Malformed
+// backup gives ErrBackupCorrupted, failed authentication gives
+// ErrBackupAuth and checksum mismatch gives ErrBackupChecksum. Curve
+// must be registered.
+func ImportBackup(data, passphrase []byte) (*PrivateKey, error) {
+	var b backup
+	rest, err := asn1.Unmarshal(data, &b)
+	if err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupCorrupted)
+	}
+	if b.Version != 0 {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: unsupported version %d", b.Version)
+	}
+	if err = checkPBKDF2Params(b.Salt, b.Iterations); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w: %s", ErrBackupCorrupted, err)
+	}
+	if len(b.Nonce) != gost3412128.BlockSize ||
+		b.Nonce[0]&0x80 > 0 ||
+		len(b.Encrypted) <= gost3412128.BlockSize {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupCorrupted)
+	}
+	aead, err := backupAEAD(passphrase, b.Salt, b.Iterations)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", err)
+	}
+	payloadRaw, err := aead.Open(nil, b.Nonce, b.Encrypted, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupAuth)
+	}
+	defer zeroizeBytes(payloadRaw)
+	var payload backupPayload
+	if rest, err = asn1.Unmarshal(payloadRaw, &payload); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupCorrupted)
+	}
+	if !hmac.Equal(backupChecksum(payload.Key.FullBytes), payload.Checksum) {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupChecksum)
+	}
+	var key backupKey
+	if rest, err = asn1.Unmarshal(payload.Key.FullBytes, &key); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", ErrBackupCorrupted)
+	}
+	defer zeroizeBytes(key.Key)
+	c := CurveByOID(key.Curve)
+	if c == nil {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: unknown curve %s", key.Curve)
+	}
+	prv, err := NewPrivateKeyRaw(c, key.Key)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ImportBackup: %w", err)
+	}
+	return prv, nil
+}
@@ -0,0 +1,103 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"testing"
+)
+
+func TestBackup(t *testing.T) {
+	prv, err := GenPrivateKey(CurveIdtc26gost341012512paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := prv.ExportBackup([]byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportBackup(data, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.Key.Cmp(prv.Key) != 0 || !imported.C.Equal(prv.C) {
+		t.Fatal("imported key differs")
+	}
+	if _, err = ImportBackup(data, []byte("wrong")); !errors.Is(err, ErrBackupAuth) {
+		t.Fatal("wrong passphrase", err)
+	}
+}
+
+func TestBackupTampered(t *testing.T) {
+	prv, err := GenPrivateKey(CurveIdtc26gost341012256paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passphrase := []byte("passphrase")
+	data, err := prv.ExportBackup(passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err = ImportBackup(tampered, passphrase); !errors.Is(err, ErrBackupAuth) {
+		t.Fatal("tampered ciphertext", err)
+	}
+	if _, err = ImportBackup(data[:len(data)-1], passphrase); !errors.Is(err, ErrBackupCorrupted) {
+		t.Fatal("truncated backup", err)
+	}
+	key, err := asn1.Marshal(backupKey{Curve: prv.C.OID, Key: prv.Raw()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksum := backupChecksum(key)
+	checksum[0] ^= 1
+	payload, err := asn1.Marshal(backupPayload{
+		Key:      asn1.RawValue{FullBytes: key},
+		Checksum: checksum,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err = sealBackup(payload, passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ImportBackup(data, passphrase); !errors.Is(err, ErrBackupChecksum) {
+		t.Fatal("checksum mismatch", err)
+	}
+}
+
+func TestBackupLimits(t *testing.T) {
+	nonce := make([]byte, 16)
+	for name, b := range map[string]backup{
+		"iterations": {Salt: make([]byte, 16), Iterations: 1<<31 - 1, Nonce: nonce, Encrypted: make([]byte, 32)},
+		"salt":       {Salt: nil, Iterations: PKCS8Iterations, Nonce: nonce, Encrypted: make([]byte, 32)},
+	} {
+		data, err := asn1.Marshal(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ImportBackup(data, []byte("passphrase")); !errors.Is(err, ErrBackupCorrupted) {
+			t.Fatal(name, err)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"unsafe"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+const baseTableMagic = "GOSTBASE"
+
+// Precompute 2^i multiples of the basic point, so ScalarBaseMult
+// replaces doublings with the table lookups. Table takes
+// Q.BitLen() points.
+func (c *Curve) PrecomputeBase() {
+	table := make([][2]*big.Int, c.Q.BitLen())
+	x := big.NewInt(0).Set(c.X)
+	y := big.NewInt(0).Set(c.Y)
+	for i := 0; i < len(table); i++ {
+		table[i] = [2]*big.Int{big.NewInt(0).Set(x), big.NewInt(0).Set(y)}
+		c.add(x, y, x, y)
+	}
+	c.baseTable.Store(table)
+}
+
+func (c *Curve) loadedBaseTable() [][2]*big.Int {
+	table, _ := c.baseTable.Load().([][2]*big.Int)
+	return table
+}
+
+// Multiply the basic point by degree. Precomputed table is used if
+// PrecomputeBase or LoadBaseTable was called, otherwise it is Exp.
+func (c *Curve) ScalarBaseMult(degree *big.Int) (*big.Int, *big.Int, error) {
+	table := c.loadedBaseTable()
+	if table == nil || degree.Sign() <= 0 || degree.BitLen() > len(table) {
+		return c.Exp(degree, c.X, c.Y)
+	}
+	x := big.NewInt(0)
+	y := big.NewInt(0)
+	isInfinity := true
+	for i := 0; i < degree.BitLen(); i++ {
+		if degree.Bit(i) == 1 {
+			isInfinity = c.addInf(x, y, isInfinity, table[i][0], table[i][1], false)
+		}
+	}
+	if isInfinity {
+		return nil, nil, errors.New("gogost/gost3410: result is at infinity")
+	}
+	return x, y, nil
+}
+
+// Get the basic point table size: number of its entries and
+// approximate memory it occupies in bytes, including big.Int headers.
+// If the table is not computed (or loaded) yet, then expected values
+// are returned, so the caller can decide whether to precompute it.
+func (c *Curve) BaseTableStats() (entries, size int, computed bool) {
+	const bigIntSize = int(unsafe.Sizeof(big.Int{}))
+	const entrySize = int(unsafe.Sizeof([2]*big.Int{}))
+	table := c.loadedBaseTable()
+	if table == nil {
+		entries = c.Q.BitLen()
+		words := (8*c.PointSize() + bits.UintSize - 1) / bits.UintSize
+		return entries, entries * (entrySize + 2*(bigIntSize+words*bits.UintSize/8)), false
+	}
+	for _, p := range table {
+		size += entrySize + 2*bigIntSize
+		size += (cap(p[0].Bits()) + cap(p[1].Bits())) * bits.UintSize / 8
+	}
+	return len(table), size, true
+}
+
+func (c *Curve) baseTableChecksum(entries []byte) []byte {
+	pointSize := c.PointSize()
+	h := gost34112012256.New()
+	for _, v := range []*big.Int{c.P, c.Q, c.A, c.B, c.X, c.Y} {
+		h.Write(pad(v.Bytes(), pointSize))
+	}
+	h.Write(entries)
+	return h.Sum(nil)
+}
+
+// Marshal precomputed basic point table, so it can be embedded and
+// later loaded with LoadBaseTable. Format is "GOSTBASE" magic,
+// Streebog-256 checksum over curve parameters and entries, then
+// BE(X)||BE(Y) entries.
+func (c *Curve) MarshalBaseTable() ([]byte, error) {
+	table := c.loadedBaseTable()
+	if table == nil {
+		return nil, errors.New("gogost/gost3410: basic point table is not precomputed")
+	}
+	pointSize := c.PointSize()
+	entries := make([]byte, 0, len(table)*2*pointSize)
+	for _, p := range table {
+		entries = append(entries, pad(p[0].Bytes(), pointSize)...)
+		entries = append(entries, pad(p[1].Bytes(), pointSize)...)
+	}
+	data := append([]byte(baseTableMagic), c.baseTableChecksum(entries)...)
+	return append(data, entries...), nil
+}
+
+// Load basic point table made by MarshalBaseTable. Checksum must match
+// the curve parameters, table must start with the basic point and all
+// the points must be on the curve.
+func (c *Curve) LoadBaseTable(data []byte) error {
+	pointSize := c.PointSize()
+	entriesLen := c.Q.BitLen() * 2 * pointSize
+	expectedLen := len(baseTableMagic) + gost34112012256.Size + entriesLen
+	if len(data) != expectedLen {
+		return fmt.Errorf("gogost/gost3410.Curve.LoadBaseTable: len(data)=%d != %d", len(data), expectedLen)
+	}
+	if !bytes.HasPrefix(data, []byte(baseTableMagic)) {
+		return errors.New("gogost/gost3410.Curve.LoadBaseTable: no magic prefix")
+	}
+	data = data[len(baseTableMagic):]
+	checksum := data[:gost34112012256.Size]
+	entries := data[gost34112012256.Size:]
+	if !bytes.Equal(checksum, c.baseTableChecksum(entries)) {
+		return errors.New("gogost/gost3410.Curve.LoadBaseTable: checksum mismatch")
+	}
+	table := make([][2]*big.Int, c.Q.BitLen())
+	for i := 0; i < len(table); i++ {
+		x := bytes2big(entries[:pointSize])
+		y := bytes2big(entries[pointSize : 2*pointSize])
+		entries = entries[2*pointSize:]
+		if !c.IsOnCurve(x, y) {
+			return fmt.Errorf("gogost/gost3410.Curve.LoadBaseTable: entry %d is not on the curve", i)
+		}
+		table[i] = [2]*big.Int{x, y}
+	}
+	if table[0][0].Cmp(c.X) != 0 || table[0][1].Cmp(c.Y) != 0 {
+		return errors.New("gogost/gost3410.Curve.LoadBaseTable: table does not start with basic point")
+	}
+	c.baseTable.Store(table)
+	return nil
+}
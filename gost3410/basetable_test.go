@@ -0,0 +1,108 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestScalarBaseMult(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	c.PrecomputeBase()
+	for i := 0; i < 10; i++ {
+		raw := make([]byte, c.PointSize())
+		rand.Read(raw)
+		k := big.NewInt(0).SetBytes(raw)
+		k.Mod(k, c.Q)
+		if k.Sign() == 0 {
+			continue
+		}
+		x, y, err := c.ScalarBaseMult(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectedX, expectedY, err := c.Exp(k, c.X, c.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+			t.FailNow()
+		}
+	}
+	if _, _, err := c.ScalarBaseMult(c.Q); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestBaseTableRoundTrip(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	if _, err := c.MarshalBaseTable(); err == nil {
+		t.FailNow()
+	}
+	c.PrecomputeBase()
+	data, err := c.MarshalBaseTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := CurveIdtc26gost341012256paramSetA()
+	if err = loaded.LoadBaseTable(data); err != nil {
+		t.Fatal(err)
+	}
+	k := big.NewInt(123456789)
+	x, y, err := loaded.ScalarBaseMult(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedX, expectedY, _ := c.ScalarBaseMult(k)
+	if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+		t.FailNow()
+	}
+	if err = CurveIdtc26gost341012256paramSetB().LoadBaseTable(data); err == nil {
+		t.Fatal("table for another curve is loaded")
+	}
+	data[len(data)-1] ^= 1
+	if err = loaded.LoadBaseTable(data); err == nil {
+		t.Fatal("corrupted table is loaded")
+	}
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	c := CurveIdtc26gost341012256paramSetB()
+	c.PrecomputeBase()
+	k := big.NewInt(0).Sub(c.Q, bigInt2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.ScalarBaseMult(k)
+	}
+}
+
+func TestBaseTableStats(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetB()
+	entries, size, computed := c.BaseTableStats()
+	if computed || entries != c.Q.BitLen() || size <= entries*2*c.PointSize() {
+		t.Fatal(entries, size, computed)
+	}
+	c.PrecomputeBase()
+	entriesComputed, sizeComputed, computed := c.BaseTableStats()
+	if !computed || entriesComputed != entries {
+		t.FailNow()
+	}
+	if sizeComputed < size/2 || sizeComputed > 2*size {
+		t.Fatal("estimation is far from real size", size, sizeComputed)
+	}
+}
@@ -0,0 +1,122 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+type certValidity struct {
+	NotBefore time.Time
+	NotAfter  time.Time
+}
+
+type tbsCertificate struct {
+	Raw                asn1.RawContent
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       *big.Int
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Issuer             asn1.RawValue
+	Validity           certValidity
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	IssuerUniqueID     asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"optional,explicit,tag:3"`
+}
+
+type certificate struct {
+	TBSCertificate     tbsCertificate
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	SignatureValue     asn1.BitString
+}
+
+// X.509 certificate with GOST R 34.10-2012 public key. Standard
+// library's crypto/x509 does not know GOST algorithms, so this is
+// a minimal replacement.
+type GOSTCertificate struct {
+	Raw                []byte
+	RawTBSCertificate  []byte
+	RawIssuer          []byte
+	RawSubject         []byte
+	SerialNumber       *big.Int
+	Issuer             pkix.Name
+	Subject            pkix.Name
+	NotBefore          time.Time
+	NotAfter           time.Time
+	PublicKey          *PublicKey
+	SignatureAlgorithm asn1.ObjectIdentifier
+	Signature          []byte
+	Extensions         []pkix.Extension
+}
+
+// Parse DER-encoded X.509 certificate with GOST public key.
+func ParseGOSTCertificate(der []byte) (*GOSTCertificate, error) {
+	var cer certificate
+	rest, err := asn1.Unmarshal(der, &cer)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseGOSTCertificate: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParseGOSTCertificate: trailing data")
+	}
+	tbs := &cer.TBSCertificate
+	if !tbs.SignatureAlgorithm.Algorithm.Equal(cer.SignatureAlgorithm.Algorithm) {
+		return nil, errors.New("gogost/gost3410.ParseGOSTCertificate: signature algorithms mismatch")
+	}
+	pub, err := ParsePKIXPublicKey(tbs.PublicKey.FullBytes)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseGOSTCertificate: %w", err)
+	}
+	var issuer, subject pkix.RDNSequence
+	if _, err = asn1.Unmarshal(tbs.Issuer.FullBytes, &issuer); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseGOSTCertificate: issuer: %w", err)
+	}
+	if _, err = asn1.Unmarshal(tbs.Subject.FullBytes, &subject); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseGOSTCertificate: subject: %w", err)
+	}
+	c := GOSTCertificate{
+		Raw:                der,
+		RawTBSCertificate:  tbs.Raw,
+		RawIssuer:          tbs.Issuer.FullBytes,
+		RawSubject:         tbs.Subject.FullBytes,
+		SerialNumber:       tbs.SerialNumber,
+		NotBefore:          tbs.Validity.NotBefore,
+		NotAfter:           tbs.Validity.NotAfter,
+		PublicKey:          pub,
+		SignatureAlgorithm: cer.SignatureAlgorithm.Algorithm,
+		Signature:          cer.SignatureValue.RightAlign(),
+		Extensions:         tbs.Extensions,
+	}
+	c.Issuer.FillFromRDNSequence(&issuer)
+	c.Subject.FillFromRDNSequence(&subject)
+	return &c, nil
+}
+
+// Verify certificate's signature with the issuer's public key.
+// Certificate's signature value is the reversed native form one, as
+// PrivateKeyReverseDigestAndSignature produces.
+func (cer *GOSTCertificate) CheckSignature(pub *PublicKey) (bool, error) {
+	sig := make([]byte, len(cer.Signature))
+	copy(sig, cer.Signature)
+	reverse(sig)
+	return VerifyByAlgorithm(cer.SignatureAlgorithm, pub, cer.RawTBSCertificate, sig)
+}
@@ -0,0 +1,125 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Contents of the certificate created by CreateSelfSignedCert and
+// CreateCert.
+type CertTemplate struct {
+	SerialNumber *big.Int
+	Subject      pkix.Name
+	NotBefore    time.Time
+	NotAfter     time.Time
+	Extensions   []pkix.Extension
+}
+
+// Create DER-encoded self-signed X.509v3 certificate for prv's public
+// key, signed with GOST R 34.10-2012 with Streebog of the curve's size.
+// Issuer is the same as the subject. Nonce is read from crypto/rand.
+func CreateSelfSignedCert(prv *PrivateKey, template CertTemplate) ([]byte, error) {
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CreateSelfSignedCert: %w", err)
+	}
+	name, err := asn1.Marshal(template.Subject.ToRDNSequence())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CreateSelfSignedCert: %w", err)
+	}
+	der, err := createCert(template, pub, name, prv)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CreateSelfSignedCert: %w", err)
+	}
+	return der, nil
+}
+
+// Create DER-encoded X.509v3 certificate for pub, issued by the issuer
+// certificate's owner, like CreateSelfSignedCert does. issuerPrv must
+// correspond to the issuer's certificate.
+func CreateCert(template CertTemplate, pub *PublicKey, issuer *GOSTCertificate, issuerPrv *PrivateKey) ([]byte, error) {
+	issuerPub, err := issuerPrv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CreateCert: %w", err)
+	}
+	if !issuerPub.Equal(issuer.PublicKey) {
+		return nil, errors.New("gogost/gost3410.CreateCert: issuer's certificate does not match the key")
+	}
+	der, err := createCert(template, pub, issuer.RawSubject, issuerPrv)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CreateCert: %w", err)
+	}
+	return der, nil
+}
+
+func createCert(template CertTemplate, pub *PublicKey, issuer []byte, prv *PrivateKey) ([]byte, error) {
+	if template.SerialNumber == nil || template.SerialNumber.Sign() <= 0 {
+		return nil, errors.New("serial number must be positive")
+	}
+	if !template.NotAfter.After(template.NotBefore) {
+		return nil, errors.New("invalid validity period")
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := asn1.Marshal(template.Subject.ToRDNSequence())
+	if err != nil {
+		return nil, err
+	}
+	sigAlg := pkix.AlgorithmIdentifier{Algorithm: oidTc26SignWithDigestGost341012256}
+	if prv.C.Is512() {
+		sigAlg.Algorithm = oidTc26SignWithDigestGost341012512
+	}
+	tbsRaw, err := asn1.Marshal(tbsCertificate{
+		Version:            2,
+		SerialNumber:       template.SerialNumber,
+		SignatureAlgorithm: sigAlg,
+		Issuer:             asn1.RawValue{FullBytes: issuer},
+		Validity: certValidity{
+			NotBefore: template.NotBefore.UTC(),
+			NotAfter:  template.NotAfter.UTC(),
+		},
+		Subject:    asn1.RawValue{FullBytes: subject},
+		PublicKey:  asn1.RawValue{FullBytes: spki},
+		Extensions: template.Extensions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	h := curveHash(prv.C)
+	h.Write(tbsRaw)
+	sign, err := prv.SignDigest(curveDigest(h), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	reverse(sign)
+	return asn1.Marshal(certificate{
+		TBSCertificate:     tbsCertificate{Raw: tbsRaw},
+		SignatureAlgorithm: sigAlg,
+		SignatureValue:     asn1.BitString{Bytes: sign, BitLength: 8 * len(sign)},
+	})
+}
@@ -0,0 +1,105 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCreateSelfSignedCert(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		der, err := CreateSelfSignedCert(prv, CertTemplate{
+			SerialNumber: big.NewInt(123),
+			Subject:      pkix.Name{CommonName: "GoGOST test CA"},
+			NotBefore:    notBefore,
+			NotAfter:     notBefore.AddDate(1, 0, 0),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		cer, err := ParseGOSTCertificate(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cer.Subject.CommonName != "GoGOST test CA" ||
+			cer.Issuer.CommonName != "GoGOST test CA" ||
+			cer.SerialNumber.Int64() != 123 ||
+			!cer.NotBefore.Equal(notBefore) ||
+			cer.NotAfter.Year() != 2024 {
+			t.Fatal("fields mismatch")
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cer.PublicKey.Equal(pub) {
+			t.Fatal("public key mismatch")
+		}
+		if valid, err := cer.CheckSignature(cer.PublicKey); err != nil || !valid {
+			t.Fatal(c.Name, err)
+		}
+		cer.RawTBSCertificate[len(cer.RawTBSCertificate)-1] ^= 0x01
+		if valid, _ := cer.CheckSignature(cer.PublicKey); valid {
+			t.Fatal("accepted altered certificate")
+		}
+	}
+}
+
+func TestCreateSelfSignedCertInvalidTemplate(t *testing.T) {
+	prv, err := GenPrivateKey(CurveIdtc26gost341012256paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if _, err = CreateSelfSignedCert(prv, CertTemplate{
+		NotBefore: now,
+		NotAfter:  now.Add(time.Hour),
+	}); err == nil {
+		t.Fatal("missing serial is accepted")
+	}
+	if _, err = CreateSelfSignedCert(prv, CertTemplate{
+		SerialNumber: bigInt1,
+		NotBefore:    now,
+		NotAfter:     now,
+	}); err == nil {
+		t.Fatal("empty validity is accepted")
+	}
+}
+
+func TestContainerCertificateSignature(t *testing.T) {
+	_, cer, err := ParseGogostContainer(gogostContainerFixture, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid, err := cer.CheckSignature(cer.PublicKey); err != nil || !valid {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,162 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"fmt"
+	"time"
+)
+
+var (
+	oidBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidKeyUsage         = asn1.ObjectIdentifier{2, 5, 29, 15}
+)
+
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+// Get the first critical extension, that is not processed by
+// VerifyChain, if any.
+func (cer *GOSTCertificate) unknownCritical() asn1.ObjectIdentifier {
+	for _, ext := range cer.Extensions {
+		if ext.Critical &&
+			!ext.Id.Equal(oidBasicConstraints) &&
+			!ext.Id.Equal(oidKeyUsage) {
+			return ext.Id
+		}
+	}
+	return nil
+}
+
+// Can the certificate issue other ones with pathLen intermediate CA
+// certificates below it: it must have basic constraints extension with
+// cA set, pathLen must not exceed its path length constraint, if any,
+// and key usage extension, if any, must allow certificates signing.
+func (cer *GOSTCertificate) mayIssue(pathLen int) bool {
+	var bc *basicConstraints
+	for _, ext := range cer.Extensions {
+		switch {
+		case ext.Id.Equal(oidBasicConstraints):
+			if bc != nil {
+				return false
+			}
+			bc = new(basicConstraints)
+			if rest, err := asn1.Unmarshal(ext.Value, bc); err != nil || len(rest) > 0 {
+				return false
+			}
+		case ext.Id.Equal(oidKeyUsage):
+			var ku asn1.BitString
+			if rest, err := asn1.Unmarshal(ext.Value, &ku); err != nil || len(rest) > 0 {
+				return false
+			}
+			if ku.At(5) == 0 { // keyCertSign
+				return false
+			}
+		}
+	}
+	return bc != nil && bc.IsCA && (bc.MaxPathLen < 0 || pathLen <= bc.MaxPathLen)
+}
+
+// Is the certificate issued by the issuer with pathLen intermediate CA
+// certificates below it: names match, issuer may issue certificates
+// and the signature is valid.
+func (cer *GOSTCertificate) issuedBy(issuer *GOSTCertificate, pathLen int) bool {
+	if !bytes.Equal(cer.RawIssuer, issuer.RawSubject) || !issuer.mayIssue(pathLen) {
+		return false
+	}
+	valid, err := cer.CheckSignature(issuer.PublicKey)
+	return err == nil && valid
+}
+
+func checkValidity(cer *GOSTCertificate, now time.Time) error {
+	if now.Before(cer.NotBefore) {
+		return fmt.Errorf("certificate %q is not valid yet", cer.Subject)
+	}
+	if now.After(cer.NotAfter) {
+		return fmt.Errorf("certificate %q has expired", cer.Subject)
+	}
+	if id := cer.unknownCritical(); id != nil {
+		return fmt.Errorf("certificate %q has unsupported critical extension %s", cer.Subject, id)
+	}
+	return nil
+}
+
+func parseCertificates(ders [][]byte) ([]*GOSTCertificate, error) {
+	cers := make([]*GOSTCertificate, 0, len(ders))
+	for _, der := range ders {
+		cer, err := ParseGOSTCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		cers = append(cers, cer)
+	}
+	return cers, nil
+}
+
+// Verify the chain of DER-encoded GOST certificates from the leaf up to
+// one of the trusted roots, through the intermediates given in any
+// order. Each certificate must be within its validity period, must not
+// have critical extensions other than basic constraints and key usage,
+// its issuer must match the issuing certificate's subject, that must be
+// a CA by basic constraints with not exceeded path length constraint
+// and allowed to sign certificates by key usage, and its signature must
+// be valid with the issuer's key. Roots are trusted as is: their own
+// signatures are not checked. Revocation and other extensions are not
+// checked either.
+func VerifyChain(leaf []byte, intermediates, roots [][]byte) error {
+	now := time.Now()
+	cer, err := ParseGOSTCertificate(leaf)
+	if err != nil {
+		return fmt.Errorf("gogost/gost3410.VerifyChain: leaf: %w", err)
+	}
+	inters, err := parseCertificates(intermediates)
+	if err != nil {
+		return fmt.Errorf("gogost/gost3410.VerifyChain: intermediate: %w", err)
+	}
+	trusted, err := parseCertificates(roots)
+	if err != nil {
+		return fmt.Errorf("gogost/gost3410.VerifyChain: root: %w", err)
+	}
+	used := make([]bool, len(inters))
+	pathLen := 0
+Chain:
+	for {
+		if err = checkValidity(cer, now); err != nil {
+			return fmt.Errorf("gogost/gost3410.VerifyChain: %w", err)
+		}
+		for _, root := range trusted {
+			if cer.issuedBy(root, pathLen) {
+				if err = checkValidity(root, now); err != nil {
+					return fmt.Errorf("gogost/gost3410.VerifyChain: %w", err)
+				}
+				return nil
+			}
+		}
+		for i, inter := range inters {
+			if !used[i] && cer.issuedBy(inter, pathLen) {
+				used[i] = true
+				cer = inter
+				pathLen++
+				continue Chain
+			}
+		}
+		return fmt.Errorf("gogost/gost3410.VerifyChain: no issuer of %q is found", cer.Subject)
+	}
+}
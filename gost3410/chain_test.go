@@ -0,0 +1,224 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+)
+
+type chainFixture struct {
+	prv *PrivateKey
+	cer *GOSTCertificate
+	der []byte
+}
+
+func basicConstraintsExt(t *testing.T, isCA bool, maxPathLen int) pkix.Extension {
+	bc, err := asn1.Marshal(basicConstraints{IsCA: isCA, MaxPathLen: maxPathLen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pkix.Extension{Id: oidBasicConstraints, Critical: true, Value: bc}
+}
+
+func newChainCert(
+	t *testing.T, c *Curve, name string, serial int64,
+	notBefore, notAfter time.Time, isCA bool, issuer *chainFixture,
+) *chainFixture {
+	return newChainCertExts(
+		t, c, name, serial, notBefore, notAfter,
+		[]pkix.Extension{basicConstraintsExt(t, isCA, -1)}, issuer,
+	)
+}
+
+func newChainCertExts(
+	t *testing.T, c *Curve, name string, serial int64,
+	notBefore, notAfter time.Time, exts []pkix.Extension, issuer *chainFixture,
+) *chainFixture {
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := CertTemplate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		Extensions:   exts,
+	}
+	var der []byte
+	if issuer == nil {
+		der, err = CreateSelfSignedCert(prv, template)
+	} else {
+		der, err = CreateCert(template, pub, issuer.cer, issuer.prv)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	cer, err := ParseGOSTCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &chainFixture{prv, cer, der}
+}
+
+func TestVerifyChain(t *testing.T) {
+	c256 := CurveIdtc26gost341012256paramSetA()
+	c512 := CurveIdtc26gost341012512paramSetA()
+	now := time.Now()
+	notBefore := now.Add(-time.Hour)
+	notAfter := now.AddDate(1, 0, 0)
+	root := newChainCert(t, c512, "root", 1, notBefore, notAfter, true, nil)
+	inter := newChainCert(t, c256, "intermediate", 2, notBefore, notAfter, true, root)
+	leaf := newChainCert(t, c256, "leaf", 3, notBefore, notAfter, false, inter)
+
+	if err := VerifyChain(leaf.der, [][]byte{inter.der}, [][]byte{root.der}); err != nil {
+		t.Fatal(err)
+	}
+	other := newChainCert(t, c256, "other", 4, notBefore, notAfter, true, nil)
+	if err := VerifyChain(
+		leaf.der, [][]byte{other.der, inter.der}, [][]byte{other.der, root.der},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChain(root.der, nil, [][]byte{root.der}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := VerifyChain(leaf.der, nil, [][]byte{root.der}); err == nil {
+		t.Fatal("missing intermediate")
+	}
+	if err := VerifyChain(leaf.der, [][]byte{inter.der}, [][]byte{other.der}); err == nil {
+		t.Fatal("untrusted root")
+	}
+	if err := VerifyChain(leaf.der, [][]byte{inter.der}, nil); err == nil {
+		t.Fatal("no roots")
+	}
+
+	// The same names, but another key of the root
+	fakeRoot := newChainCert(t, c512, "root", 1, notBefore, notAfter, true, nil)
+	if err := VerifyChain(leaf.der, [][]byte{inter.der}, [][]byte{fakeRoot.der}); err == nil {
+		t.Fatal("invalid signature")
+	}
+
+	expired := newChainCert(t, c256, "intermediate", 5, now.AddDate(-2, 0, 0), now.AddDate(-1, 0, 0), true, root)
+	leafExpired := newChainCert(t, c256, "leaf", 6, notBefore, notAfter, false, expired)
+	if err := VerifyChain(leafExpired.der, [][]byte{expired.der}, [][]byte{root.der}); err == nil {
+		t.Fatal("expired intermediate")
+	}
+	future := newChainCert(t, c256, "leaf", 7, now.Add(time.Hour), notAfter, false, inter)
+	if err := VerifyChain(future.der, [][]byte{inter.der}, [][]byte{root.der}); err == nil {
+		t.Fatal("not yet valid leaf")
+	}
+
+	// Leaf is not a CA, so it can not issue certificates
+	subLeaf := newChainCert(t, c256, "sub-leaf", 8, notBefore, notAfter, false, leaf)
+	if err := VerifyChain(subLeaf.der, [][]byte{leaf.der, inter.der}, [][]byte{root.der}); err == nil {
+		t.Fatal("non-CA issuer")
+	}
+
+	if err := VerifyChain([]byte("garbage"), nil, [][]byte{root.der}); err == nil {
+		t.FailNow()
+	}
+	if _, err := CreateCert(CertTemplate{
+		SerialNumber: big.NewInt(9),
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}, leaf.cer.PublicKey, inter.cer, root.prv); err == nil {
+		t.Fatal("issuer's certificate and key mismatch")
+	}
+}
+
+func TestVerifyChainExtensions(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	now := time.Now()
+	notBefore := now.Add(-time.Hour)
+	notAfter := now.AddDate(1, 0, 0)
+	root := newChainCert(t, c, "root", 1, notBefore, notAfter, true, nil)
+
+	// CA without basic constraints at all
+	noBC := newChainCertExts(t, c, "no-bc", 2, notBefore, notAfter, nil, root)
+	leaf := newChainCert(t, c, "leaf", 3, notBefore, notAfter, false, noBC)
+	if err := VerifyChain(leaf.der, [][]byte{noBC.der}, [][]byte{root.der}); err == nil {
+		t.Fatal("issuer without basic constraints")
+	}
+	noBCRoot := newChainCertExts(t, c, "no-bc-root", 4, notBefore, notAfter, nil, nil)
+	leaf = newChainCert(t, c, "leaf", 5, notBefore, notAfter, false, noBCRoot)
+	if err := VerifyChain(leaf.der, nil, [][]byte{noBCRoot.der}); err == nil {
+		t.Fatal("root without basic constraints")
+	}
+
+	// Path length constraint
+	rootPath0 := newChainCertExts(t, c, "root-path0", 6, notBefore, notAfter,
+		[]pkix.Extension{basicConstraintsExt(t, true, 0)}, nil)
+	leaf = newChainCert(t, c, "leaf", 7, notBefore, notAfter, false, rootPath0)
+	if err := VerifyChain(leaf.der, nil, [][]byte{rootPath0.der}); err != nil {
+		t.Fatal(err)
+	}
+	inter := newChainCert(t, c, "intermediate", 8, notBefore, notAfter, true, rootPath0)
+	leaf = newChainCert(t, c, "leaf", 9, notBefore, notAfter, false, inter)
+	if err := VerifyChain(leaf.der, [][]byte{inter.der}, [][]byte{rootPath0.der}); err == nil {
+		t.Fatal("path length constraint is exceeded")
+	}
+
+	// Key usage without keyCertSign
+	ku, err := asn1.Marshal(asn1.BitString{Bytes: []byte{0x80}, BitLength: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	noCertSign := newChainCertExts(t, c, "no-cert-sign", 10, notBefore, notAfter, []pkix.Extension{
+		basicConstraintsExt(t, true, -1),
+		{Id: oidKeyUsage, Critical: true, Value: ku},
+	}, root)
+	leaf = newChainCert(t, c, "leaf", 11, notBefore, notAfter, false, noCertSign)
+	if err = VerifyChain(leaf.der, [][]byte{noCertSign.der}, [][]byte{root.der}); err == nil {
+		t.Fatal("issuer without keyCertSign")
+	}
+	if ku, err = asn1.Marshal(asn1.BitString{Bytes: []byte{0x04}, BitLength: 6}); err != nil {
+		t.Fatal(err)
+	}
+	certSign := newChainCertExts(t, c, "cert-sign", 14, notBefore, notAfter, []pkix.Extension{
+		basicConstraintsExt(t, true, -1),
+		{Id: oidKeyUsage, Critical: true, Value: ku},
+	}, root)
+	leaf = newChainCert(t, c, "leaf", 15, notBefore, notAfter, false, certSign)
+	if err = VerifyChain(leaf.der, [][]byte{certSign.der}, [][]byte{root.der}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown extensions
+	unknown := pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}, Value: []byte{5, 0}}
+	leaf = newChainCertExts(t, c, "leaf", 12, notBefore, notAfter, []pkix.Extension{unknown}, root)
+	if err = VerifyChain(leaf.der, nil, [][]byte{root.der}); err != nil {
+		t.Fatal(err)
+	}
+	unknown.Critical = true
+	leaf = newChainCertExts(t, c, "leaf", 13, notBefore, notAfter, []pkix.Extension{unknown}, root)
+	if err = VerifyChain(leaf.der, nil, [][]byte{root.der}); err == nil {
+		t.Fatal("unknown critical extension")
+	}
+}
@@ -0,0 +1,173 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type cmsContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type cmsEncapContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     asn1.RawValue `asn1:"optional"` // [0] EXPLICIT OCTET STRING
+}
+
+type cmsIssuerAndSerialNumber struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+type cmsSignerInfo struct {
+	Version            int
+	SID                cmsIssuerAndSerialNumber
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+}
+
+type cmsSignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo cmsEncapContentInfo
+	Certificates     asn1.RawValue   `asn1:"optional,tag:0"`
+	SignerInfos      []cmsSignerInfo `asn1:"set"`
+}
+
+// Verify CMS (RFC 5652) SignedData made by SignCMS, returning the
+// signer's certificate and the signed content. For detached signature
+// the content must be supplied by the caller, for attached one it must
+// be either nil or equal to the embedded content. Only the signature is
+// checked, not the trust of the certificate.
+func VerifyCMS(der, content []byte) (*GOSTCertificate, []byte, error) {
+	var ci cmsContentInfo
+	rest, err := asn1.Unmarshal(der, &ci)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.VerifyCMS: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: trailing data")
+	}
+	if !ci.ContentType.Equal(oidCMSSignedData) ||
+		ci.Content.Class != asn1.ClassContextSpecific || ci.Content.Tag != 0 {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: not SignedData")
+	}
+	var sd cmsSignedData
+	if rest, err = asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.VerifyCMS: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: trailing data")
+	}
+	if !sd.EncapContentInfo.EContentType.Equal(oidCMSData) {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: unsupported content type")
+	}
+	var embedded []byte
+	ec := sd.EncapContentInfo.EContent
+	attached := len(ec.FullBytes) > 0
+	if attached {
+		if ec.Class != asn1.ClassContextSpecific || ec.Tag != 0 || !ec.IsCompound {
+			return nil, nil, errors.New("gogost/gost3410.VerifyCMS: invalid encapsulated content")
+		}
+		if rest, err = asn1.Unmarshal(ec.Bytes, &embedded); err != nil {
+			return nil, nil, fmt.Errorf("gogost/gost3410.VerifyCMS: %w", err)
+		}
+		if len(rest) > 0 {
+			return nil, nil, errors.New("gogost/gost3410.VerifyCMS: trailing data")
+		}
+		if embedded == nil {
+			embedded = []byte{}
+		}
+	}
+	switch {
+	case !attached && content == nil:
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: detached signature requires content")
+	case !attached:
+	case content == nil:
+		content = embedded
+	case !bytes.Equal(content, embedded):
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: content differs from the embedded one")
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: exactly one signer is supported")
+	}
+	si := sd.SignerInfos[0]
+	cer, err := cmsFindCertificate(sd.Certificates.Bytes, si.SID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.VerifyCMS: %w", err)
+	}
+	pub := cer.PublicKey
+	digestOID := oidTc26Gost341112256
+	keyOID := oidTc26Gost341012256
+	sigOID := oidTc26SignWithDigestGost341012256
+	if pub.C.Is512() {
+		digestOID = oidTc26Gost341112512
+		keyOID = oidTc26Gost341012512
+		sigOID = oidTc26SignWithDigestGost341012512
+	}
+	if !si.DigestAlgorithm.Algorithm.Equal(digestOID) {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: digest algorithm does not match the key")
+	}
+	if !si.SignatureAlgorithm.Algorithm.Equal(keyOID) &&
+		!si.SignatureAlgorithm.Algorithm.Equal(sigOID) {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: signature algorithm does not match the key")
+	}
+	h := curveHash(pub.C)
+	h.Write(content)
+	sig := make([]byte, len(si.Signature))
+	copy(sig, si.Signature)
+	reverse(sig)
+	valid, err := pub.VerifyDigest(curveDigest(h), sig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.VerifyCMS: %w", err)
+	}
+	if !valid {
+		return nil, nil, errors.New("gogost/gost3410.VerifyCMS: invalid signature")
+	}
+	return cer, content, nil
+}
+
+// Find signer's certificate among CertificateSet's ones. Other
+// certificate choices and non-GOST certificates are skipped.
+func cmsFindCertificate(certs []byte, sid cmsIssuerAndSerialNumber) (*GOSTCertificate, error) {
+	for len(certs) > 0 {
+		var raw asn1.RawValue
+		var err error
+		if certs, err = asn1.Unmarshal(certs, &raw); err != nil {
+			return nil, err
+		}
+		if raw.Class != asn1.ClassUniversal || raw.Tag != asn1.TagSequence {
+			continue
+		}
+		cer, err := ParseGOSTCertificate(raw.FullBytes)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(sid.Issuer.FullBytes, cer.RawIssuer) &&
+			sid.SerialNumber.Cmp(cer.SerialNumber) == 0 {
+			return cer, nil
+		}
+	}
+	return nil, errors.New("signer's certificate is not found")
+}
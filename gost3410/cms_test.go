@@ -0,0 +1,194 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestCMSAttachedDetached(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, cer := cmsSelfSigned(t, c, 123)
+		content := []byte("some content to be signed")
+
+		attached, err := SignCMS(rand.Reader, prv, cer, content, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, got, err := VerifyCMS(attached, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) || !bytes.Equal(signer.Raw, cer.Raw) {
+			t.FailNow()
+		}
+		if _, _, err = VerifyCMS(attached, content); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err = VerifyCMS(attached, []byte("other")); err == nil {
+			t.Fatal("differing content accepted")
+		}
+
+		detached, err := SignCMS(rand.Reader, prv, cer, content, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(detached) >= len(attached) {
+			t.Fatal("detached signature contains content")
+		}
+		if _, _, err = VerifyCMS(detached, nil); err == nil {
+			t.Fatal("detached signature verified without content")
+		}
+		if _, got, err = VerifyCMS(detached, content); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.FailNow()
+		}
+		if _, _, err = VerifyCMS(detached, []byte("other")); err == nil {
+			t.Fatal("wrong content accepted")
+		}
+
+		empty, err := SignCMS(rand.Reader, prv, cer, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, got, err = VerifyCMS(empty, nil); err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || len(got) != 0 {
+			t.Fatal("empty attached content is not returned")
+		}
+		if _, _, err = VerifyCMS(empty, []byte("other")); err == nil {
+			t.Fatal("empty attached content is treated as detached")
+		}
+
+		other, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = SignCMS(rand.Reader, other, cer, content, false); err == nil {
+			t.Fatal("mismatching key accepted")
+		}
+	}
+}
+
+func cmsSelfSigned(t *testing.T, c *Curve, serial int64) (*PrivateKey, *GOSTCertificate) {
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notBefore := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	der, err := CreateSelfSignedCert(prv, CertTemplate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "cms"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(1, 0, 0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cer, err := ParseGOSTCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prv, cer
+}
+
+// Decode SignedData, alter it and encode back
+func cmsAlter(t *testing.T, der []byte, alter func(sd *cmsSignedData)) []byte {
+	var ci cmsContentInfo
+	if _, err := asn1.Unmarshal(der, &ci); err != nil {
+		t.Fatal(err)
+	}
+	var sd cmsSignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		t.Fatal(err)
+	}
+	alter(&sd)
+	raw, err := asn1.Marshal(sd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ci.Content = asn1.RawValue{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        0,
+		IsCompound: true,
+		Bytes:      raw,
+	}
+	if der, err = asn1.Marshal(ci); err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+func TestCMSCertificates(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	prv, cer := cmsSelfSigned(t, c, 1)
+	_, otherCer := cmsSelfSigned(t, c, 2)
+	content := []byte("content")
+	der, err := SignCMS(rand.Reader, prv, cer, content, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	several := cmsAlter(t, der, func(sd *cmsSignedData) {
+		sd.Certificates.FullBytes = nil
+		sd.Certificates.Bytes = append(append([]byte{}, otherCer.Raw...), cer.Raw...)
+	})
+	signer, _, err := VerifyCMS(several, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signer.Raw, cer.Raw) {
+		t.Fatal("wrong certificate is found")
+	}
+	missing := cmsAlter(t, der, func(sd *cmsSignedData) {
+		sd.Certificates.FullBytes = nil
+		sd.Certificates.Bytes = otherCer.Raw
+	})
+	if _, _, err = VerifyCMS(missing, nil); err == nil {
+		t.Fatal("missing certificate accepted")
+	}
+	for _, oid := range []asn1.ObjectIdentifier{
+		oidTc26Gost341012512,
+		oidTc26SignWithDigestGost341012512,
+	} {
+		wrongAlg := cmsAlter(t, der, func(sd *cmsSignedData) {
+			sd.SignerInfos[0].SignatureAlgorithm.Algorithm = oid
+		})
+		if _, _, err = VerifyCMS(wrongAlg, nil); err == nil {
+			t.Fatal("mismatching signature algorithm accepted")
+		}
+	}
+	withDigest := cmsAlter(t, der, func(sd *cmsSignedData) {
+		sd.SignerInfos[0].SignatureAlgorithm.Algorithm = oidTc26SignWithDigestGost341012256
+	})
+	if _, _, err = VerifyCMS(withDigest, nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,102 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Create CMS (RFC 5652) SignedData over the content with the single
+// signer, whose certificate is embedded. Content is embedded too,
+// unless detached is set: then it has to be supplied to VerifyCMS
+// separately. No signed attributes are used: content's Streebog digest
+// of the key's size is signed directly and the signature is encoded
+// the same way as in certificates.
+func SignCMS(rand io.Reader, prv *PrivateKey, cer *GOSTCertificate, content []byte, detached bool) ([]byte, error) {
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.SignCMS: %w", err)
+	}
+	if !pub.Equal(cer.PublicKey) {
+		return nil, errors.New("gogost/gost3410.SignCMS: certificate does not match the key")
+	}
+	digestAlg := pkix.AlgorithmIdentifier{Algorithm: oidTc26Gost341112256}
+	sigAlg := pkix.AlgorithmIdentifier{Algorithm: oidTc26Gost341012256}
+	if prv.C.Is512() {
+		digestAlg.Algorithm = oidTc26Gost341112512
+		sigAlg.Algorithm = oidTc26Gost341012512
+	}
+	h := curveHash(prv.C)
+	h.Write(content)
+	sign, err := prv.SignDigest(curveDigest(h), rand)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.SignCMS: %w", err)
+	}
+	reverse(sign)
+	encap := cmsEncapContentInfo{EContentType: oidCMSData}
+	if !detached {
+		octets, err := asn1.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("gogost/gost3410.SignCMS: %w", err)
+		}
+		encap.EContent = asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      octets,
+		}
+	}
+	sd, err := asn1.Marshal(cmsSignedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{digestAlg},
+		EncapContentInfo: encap,
+		Certificates: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      cer.Raw,
+		},
+		SignerInfos: []cmsSignerInfo{{
+			Version: 1,
+			SID: cmsIssuerAndSerialNumber{
+				Issuer:       asn1.RawValue{FullBytes: cer.RawIssuer},
+				SerialNumber: cer.SerialNumber,
+			},
+			DigestAlgorithm:    digestAlg,
+			SignatureAlgorithm: sigAlg,
+			Signature:          sign,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.SignCMS: %w", err)
+	}
+	return asn1.Marshal(cmsContentInfo{
+		ContentType: oidCMSSignedData,
+		Content: asn1.RawValue{
+			Class:      asn1.ClassContextSpecific,
+			Tag:        0,
+			IsCompound: true,
+			Bytes:      sd,
+		},
+	})
+}
@@ -0,0 +1,89 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+)
+
+// GoGOST-specific key container, made of ordinary PKCS #8 and X.509
+// structures:
+//
+//	GoGOSTContainer ::= SEQUENCE {
+//	    version     INTEGER, -- 0
+//	    privateKey  EncryptedPrivateKeyInfo,
+//	    certificate Certificate }
+//
+// where privateKey is made with MarshalEncryptedPKCS8. It is not
+// CryptoPro's container format (header.key, masks.key, primary.key and
+// so on) and is not compatible with it: keys exported from CryptoPro
+// must be repacked into it by other means.
+type gogostContainer struct {
+	Version     int
+	PrivateKey  asn1.RawValue
+	Certificate asn1.RawValue
+}
+
+// Marshal the key encrypted with the password, together with its
+// DER-encoded certificate, into the container.
+func MarshalGogostContainer(prv *PrivateKey, cer, password []byte) ([]byte, error) {
+	key, err := MarshalEncryptedPKCS8(prv, password)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalGogostContainer: %w", err)
+	}
+	return asn1.Marshal(gogostContainer{
+		PrivateKey:  asn1.RawValue{FullBytes: key},
+		Certificate: asn1.RawValue{FullBytes: cer},
+	})
+}
+
+// Parse the container made with MarshalGogostContainer, decrypting its
+// key with the password. Certificate's public key must match the
+// private key.
+func ParseGogostContainer(data, password []byte) (*PrivateKey, *GOSTCertificate, error) {
+	var cont gogostContainer
+	rest, err := asn1.Unmarshal(data, &cont)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.ParseGogostContainer: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, nil, errors.New("gogost/gost3410.ParseGogostContainer: trailing data")
+	}
+	if cont.Version != 0 {
+		return nil, nil, fmt.Errorf("gogost/gost3410.ParseGogostContainer: unsupported version %d", cont.Version)
+	}
+	prv, err := ParseEncryptedPKCS8(cont.PrivateKey.FullBytes, password)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.ParseGogostContainer: %w", err)
+	}
+	cer, err := ParseGOSTCertificate(cont.Certificate.FullBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.ParseGogostContainer: %w", err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.ParseGogostContainer: %w", err)
+	}
+	if !pub.Equal(cer.PublicKey) {
+		return nil, nil, errors.New("gogost/gost3410.ParseGogostContainer: certificate does not match the key")
+	}
+	return prv, cer, nil
+}
@@ -0,0 +1,94 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// Container with self-signed certificate of 256-bit paramSetA key,
+// encrypted with "password"
+var gogostContainerFixture = mustHex("" +
+	"308201ec0201003081b8306206092a864886f70d01050d3055303206092a8648" +
+	"86f70d01050c30250410c5859030eabe2a714b27c666945adaa6020227100201" +
+	"20300a06082a85030701010402301f06092a8503070101050203301204101c99" +
+	"31451b7951950ddfa3768f9418050452a9d048bd81ea0c8c25865bd23147b058" +
+	"6a0dce5ca889e410998fe0e562c400f81ae6faec88bbbf54dcbc79206317ba9f" +
+	"3af17b4bf425e890da4ca0a06ae6023fda093dd2fcf6042bc1f60ae21ccd5967" +
+	"f3e63082012a3081d8a003020102020101300a06082a85030701010302302031" +
+	"1e301c06035504031315476f474f535420636f6e7461696e6572207465737430" +
+	"1e170d3233303130313030303030305a170d3333303130313030303030305a30" +
+	"20311e301c06035504031315476f474f535420636f6e7461696e657220746573" +
+	"74305e301706082a85030701010101300b06092a850307010201010103430004" +
+	"400892e71e05df814e1fd71570b2da74a89a0b1a0c918191d29c51a9bcd61421" +
+	"e216574172785da37d55f599773c7b131e350f8ded69225a9e1d6a267c0d8d57" +
+	"05300a06082a85030701010302034100882903c0214f0c107f53b79be320d95c" +
+	"ceb2ff11215f238d6617f4683dbbab12d2d2b27df5b0db04a193fbc6ebccd407" +
+	"efdc9f11a6d0b7f87ed43e50091b1f23")
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseGogostContainer(t *testing.T) {
+	prv, cer, err := ParseGogostContainer(gogostContainerFixture, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(prv.Raw(), mustHex(
+		"a967bbedd88bd973a16cddb1ca4c7c3b193b5ac88145287667a51bc4101ec222",
+	)) {
+		t.FailNow()
+	}
+	if !prv.C.Equal(CurveIdtc26gost341012256paramSetA()) {
+		t.FailNow()
+	}
+	if cer.Subject.CommonName != "GoGOST container test" ||
+		cer.SerialNumber.Int64() != 1 ||
+		cer.NotAfter.Year() != 2033 {
+		t.FailNow()
+	}
+	if _, _, err = ParseGogostContainer(gogostContainerFixture, []byte("wrong")); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestGogostContainerRoundTrip(t *testing.T) {
+	_, cer, err := ParseGogostContainer(gogostContainerFixture, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenPrivateKey(CurveIdtc26gost341012256paramSetA(), DeterministicReader([]byte("other")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalGogostContainer(other, cer.Raw, []byte("password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = ParseGogostContainer(data, []byte("password")); err == nil {
+		t.Fatal("mismatching certificate is accepted")
+	}
+}
@@ -0,0 +1,41 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"fmt"
+	"io"
+)
+
+// Cross-sign the new public key with the old private key, for example
+// during migration from 256-bit to 512-bit keys. New key's
+// SubjectPublicKeyInfo DER is signed with SignContext under the
+// dedicated context, so the signature can not be confused with the
+// ordinary signature of the same data. Check it with VerifyCrossSign.
+func CrossSign(rand io.Reader, oldPrv *PrivateKey, newPub *PublicKey) ([]byte, error) {
+	spki, err := MarshalPKIXPublicKey(newPub)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CrossSign: %w", err)
+	}
+	sign, err := oldPrv.SignContext(rand, spki, []byte(crossSignContext))
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.CrossSign: %w", err)
+	}
+	return sign, nil
+}
@@ -0,0 +1,86 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestCrossSign(t *testing.T) {
+	c256 := CurveIdtc26gost341012256paramSetA()
+	c512 := CurveIdtc26gost341012512paramSetA()
+	for _, pair := range [][2]*Curve{{c256, c512}, {c512, c256}, {c256, c256}} {
+		oldPrv, err := GenPrivateKey(pair[0], rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oldPub, err := oldPrv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		newPrv, err := GenPrivateKey(pair[1], rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		newPub, err := newPrv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := CrossSign(rand.Reader, oldPrv, newPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sig) != 2*pair[0].PointSize() {
+			t.FailNow()
+		}
+		valid, err := VerifyCrossSign(oldPub, newPub, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.FailNow()
+		}
+		if valid, _ = VerifyCrossSign(newPub, newPub, sig); valid {
+			t.Fatal("verified with the wrong key")
+		}
+		otherPrv, err := GenPrivateKey(pair[1], rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherPub, err := otherPrv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, _ = VerifyCrossSign(oldPub, otherPub, sig); valid {
+			t.Fatal("verified over the wrong key")
+		}
+		spki, err := MarshalPKIXPublicKey(newPub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err = oldPrv.SignContext(rand.Reader, spki, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, _ = VerifyCrossSign(oldPub, newPub, sig); valid {
+			t.Fatal("signature without cross-sign context verified")
+		}
+	}
+}
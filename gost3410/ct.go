@@ -0,0 +1,66 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/subtle"
+	"math/big"
+)
+
+// Point coordinates in fixed-width big-endian form, PointSize bytes each
+type ctPoint struct {
+	x []byte
+	y []byte
+}
+
+func (c *Curve) newCTPoint(x, y *big.Int) *ctPoint {
+	pointSize := c.PointSize()
+	return &ctPoint{
+		x.FillBytes(make([]byte, pointSize)),
+		y.FillBytes(make([]byte, pointSize)),
+	}
+}
+
+func (p *ctPoint) big() (x, y *big.Int) {
+	return bytes2big(p.x), bytes2big(p.y)
+}
+
+// Set dst to a if bit is 1 and to b if it is 0, without branching on
+// the (secret) bit value. bit must be either 0 or 1.
+func ctSelect(dst *ctPoint, bit int, a, b *ctPoint) {
+	copy(dst.x, b.x)
+	copy(dst.y, b.y)
+	subtle.ConstantTimeCopy(bit, dst.x, a.x)
+	subtle.ConstantTimeCopy(bit, dst.y, a.y)
+}
+
+// Compute a^-1 mod Q, where a is within [1, Q), without secret
+// dependent branches and memory accesses of big.Int.ModInverse.
+// Fermat's little theorem exponentiation in fixed-width Montgomery
+// form is used. It falls back to ModInverse only if Q does not fit
+// that arithmetic.
+func (c *Curve) modInverseQCT(a *big.Int) *big.Int {
+	c.scalarOnce.Do(func() {
+		c.scalarFld = newField(c.Q)
+	})
+	f := c.scalarFld
+	if f == nil {
+		return big.NewInt(0).ModInverse(a, c.Q)
+	}
+	x := f.fromBig(a)
+	f.inv(&x, &x)
+	return f.toBig(&x)
+}
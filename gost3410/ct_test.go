@@ -0,0 +1,93 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math/big"
+	"testing"
+)
+
+func TestCTSelect(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	a := c.newCTPoint(c.X, c.Y)
+	x2, y2, _ := c.Exp(big.NewInt(2), c.X, c.Y)
+	b := c.newCTPoint(x2, y2)
+	dst := c.newCTPoint(big.NewInt(0), big.NewInt(0))
+	ctSelect(dst, 1, a, b)
+	if x, y := dst.big(); x.Cmp(c.X) != 0 || y.Cmp(c.Y) != 0 {
+		t.FailNow()
+	}
+	ctSelect(dst, 0, a, b)
+	if x, y := dst.big(); x.Cmp(x2) != 0 || y.Cmp(y2) != 0 {
+		t.FailNow()
+	}
+}
+
+// ctSelect must not have any conditional statements
+func TestCTSelectHasNoBranches(t *testing.T) {
+	f, err := parser.ParseFile(token.NewFileSet(), "ct.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "ctSelect" {
+			continue
+		}
+		found = true
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.IfStmt, *ast.SwitchStmt, *ast.SelectStmt, *ast.ForStmt, *ast.RangeStmt:
+				t.Errorf("branching statement at %d", n.Pos())
+			}
+			return true
+		})
+	}
+	if !found {
+		t.FailNow()
+	}
+}
+
+func TestModInverseQCT(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdtc26gost341012512paramSetB(),
+	} {
+		for _, a := range []*big.Int{bigInt1, big.NewInt(2), big.NewInt(0).Sub(c.Q, bigInt1)} {
+			if c.modInverseQCT(a).Cmp(big.NewInt(0).ModInverse(a, c.Q)) != 0 {
+				t.Fatal(c.Name, a)
+			}
+		}
+		for i := 0; i < 50; i++ {
+			a, err := rand.Int(rand.Reader, c.Q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Sign() == 0 {
+				continue
+			}
+			if c.modInverseQCT(a).Cmp(big.NewInt(0).ModInverse(a, c.Q)) != 0 {
+				t.Fatal(c.Name, a)
+			}
+		}
+	}
+}
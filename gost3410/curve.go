@@ -16,8 +16,13 @@
 package gost3410
 
 import (
+	"encoding/asn1"
 	"errors"
+	"fmt"
+	"io"
 	"math/big"
+	"sync"
+	"sync/atomic"
 )
 
 var (
@@ -26,10 +31,19 @@ var (
 	bigInt2 *big.Int = big.NewInt(2)
 	bigInt3 *big.Int = big.NewInt(3)
 	bigInt4 *big.Int = big.NewInt(4)
+
+	ErrInvalidCurveParams = errors.New("gogost/gost3410: invalid curve parameters")
+	ErrSingularCurve      = fmt.Errorf("%w: singular curve", ErrInvalidCurveParams)
+	ErrDigestSize         = errors.New("gogost/gost3410: digest size does not match the curve")
 )
 
+// Elliptic curve with its parameters. Curve must not be copied and its
+// exported fields must not be changed after creation. All its methods
+// are safe for concurrent use: lazily calculated caches are
+// initialized at most once.
 type Curve struct {
-	Name string // Just simple identifier
+	Name string                // Just simple identifier
+	OID  asn1.ObjectIdentifier // Parameters set identifier, if any
 
 	P *big.Int // Characteristic of the underlying prime field
 	Q *big.Int // Elliptic curve subgroup order
@@ -49,11 +63,59 @@ type Curve struct {
 	Y *big.Int
 
 	// Cached s/t parameters for Edwards curve points conversion
-	edS *big.Int
-	edT *big.Int
+	edOnce sync.Once
+	edS    *big.Int
+	edT    *big.Int
+
+	// Fixed-width field arithmetic context
+	fieldOnce sync.Once
+	fld       *field
+	fldA      fe
+
+	// Cached Co^-1 mod Q
+	coInvOnce sync.Once
+	coInv     *big.Int
+
+	// Fixed-width arithmetic context modulo Q
+	scalarOnce sync.Once
+	scalarFld  *field
+
+	// Exp implementation selected by TuneExp
+	expImpl atomic.Int32
+
+	// Precomputed 2^i multiples of the basic point, [][2]*big.Int
+	baseTable atomic.Value
 }
 
+// Create curve with specified parameters. e, d and co are optional:
+// missing cofactor defaults to 1. Returned errors wrap
+// ErrInvalidCurveParams.
 func NewCurve(p, q, a, b, x, y, e, d, co *big.Int) (*Curve, error) {
+	if p == nil || q == nil || a == nil || b == nil || x == nil || y == nil {
+		return nil, fmt.Errorf("%w: missing parameter", ErrInvalidCurveParams)
+	}
+	if q.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: Q must be positive%s", ErrInvalidCurveParams, verbose("Q=%d", q))
+	}
+	if co != nil && co.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: cofactor must be positive%s", ErrInvalidCurveParams, verbose("Co=%d", co))
+	}
+	// Prime order curves may have Q > P, so check the Hasse bound
+	// Co*Q <= P+1+2*sqrt(P) instead of simple P > Q comparison
+	bound := big.NewInt(0).Sqrt(p)
+	bound.Lsh(bound, 1)
+	bound.Add(bound, p)
+	bound.Add(bound, bigInt2)
+	order := big.NewInt(0).Set(q)
+	if co != nil {
+		order.Mul(order, co)
+	}
+	if p.Sign() <= 0 || order.Cmp(bound) > 0 {
+		return nil, fmt.Errorf(
+			"%w: Q does not fit P%s", ErrInvalidCurveParams,
+			verbose("Co*Q=%x > P+1+2*sqrt(P)=%x", order, bound),
+		)
+	}
 	c := Curve{
 		Name: "unknown",
 		P:    p,
@@ -63,18 +125,11 @@ func NewCurve(p, q, a, b, x, y, e, d, co *big.Int) (*Curve, error) {
 		X:    x,
 		Y:    y,
 	}
-	r1 := big.NewInt(0)
-	r2 := big.NewInt(0)
-	r1.Mul(c.Y, c.Y)
-	r1.Mod(r1, c.P)
-	r2.Mul(c.X, c.X)
-	r2.Add(r2, c.A)
-	r2.Mul(r2, c.X)
-	r2.Add(r2, c.B)
-	r2.Mod(r2, c.P)
-	c.pos(r2)
-	if r1.Cmp(r2) != 0 {
-		return nil, errors.New("gogost/gost3410: invalid curve parameters")
+	if c.isSingular() {
+		return nil, ErrSingularCurve
+	}
+	if !c.IsOnCurve(c.X, c.Y) {
+		return nil, fmt.Errorf("%w: basic point is not on the curve%s", ErrInvalidCurveParams, c.notOnCurve(c.X, c.Y))
 	}
 	if e != nil && d != nil {
 		c.E = e
@@ -88,12 +143,203 @@ func NewCurve(p, q, a, b, x, y, e, d, co *big.Int) (*Curve, error) {
 	return &c, nil
 }
 
+// Does the point with X, Y coordinates satisfy the curve's equation.
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 || y.Sign() < 0 || y.Cmp(c.P) >= 0 {
+		return false
+	}
+	r1 := big.NewInt(0)
+	r2 := big.NewInt(0)
+	r1.Mul(y, y)
+	r1.Mod(r1, c.P)
+	r2.Mul(x, x)
+	r2.Add(r2, c.A)
+	r2.Mul(r2, x)
+	r2.Add(r2, c.B)
+	r2.Mod(r2, c.P)
+	c.pos(r2)
+	return r1.Cmp(r2) == 0
+}
+
+// Get both points (x, y) and (x, P-y) with the given X coordinate,
+// computing Y as the square root of the curve's equation right side.
+// ok is false if there is no such point. If y is zero, then both
+// points are the same.
+func (c *Curve) PointsWithX(x *big.Int) (p1, p2 [2]*big.Int, ok bool) {
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 {
+		return
+	}
+	rhs := big.NewInt(0).Mul(x, x)
+	rhs.Add(rhs, c.A)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, c.B)
+	rhs.Mod(rhs, c.P)
+	y := big.NewInt(0).ModSqrt(rhs, c.P)
+	if y == nil {
+		return
+	}
+	yNeg := big.NewInt(0).Sub(c.P, y)
+	yNeg.Mod(yNeg, c.P)
+	p1 = [2]*big.Int{big.NewInt(0).Set(x), y}
+	p2 = [2]*big.Int{big.NewInt(0).Set(x), yNeg}
+	return p1, p2, true
+}
+
+// Is the curve's discriminant 4*A^3 + 27*B^2 zero modulo P. Such
+// curve has a singular point and the group law does not hold.
+func (c *Curve) isSingular() bool {
+	a3 := big.NewInt(0).Exp(c.A, bigInt3, c.P)
+	a3.Lsh(a3, 2)
+	b2 := big.NewInt(0).Mul(c.B, c.B)
+	b2.Mul(b2, big.NewInt(27))
+	a3.Add(a3, b2)
+	return a3.Mod(a3, c.P).Sign() == 0
+}
+
+// Check curve parameters sanity: P and Q are (probably) prime, curve is
+// non-singular, basic point lies on the curve and has order Q. It is
+// much more expensive than NewCurve's checks, so use it for parameters
+// from untrusted sources.
+func (c *Curve) Validate() error {
+	if c.P == nil || c.Q == nil || c.A == nil || c.B == nil ||
+		c.X == nil || c.Y == nil || c.Co == nil {
+		return errors.New("gogost/gost3410: incomplete curve parameters")
+	}
+	if !c.P.ProbablyPrime(20) {
+		return errors.New("gogost/gost3410: P is not prime")
+	}
+	if !c.Q.ProbablyPrime(20) {
+		return errors.New("gogost/gost3410: Q is not prime")
+	}
+	if c.isSingular() {
+		return ErrSingularCurve
+	}
+	if !c.IsOnCurve(c.X, c.Y) {
+		return fmt.Errorf("gogost/gost3410: basic point is not on the curve%s", c.notOnCurve(c.X, c.Y))
+	}
+	_, _, inf, err := c.ScalarMult(c.Q, c.X, c.Y)
+	if err != nil {
+		return err
+	}
+	if !inf {
+		return errors.New("gogost/gost3410: basic point's order is not Q")
+	}
+	return nil
+}
+
+// Convert the digest to e scalar used in signature generation and
+// verification: digest is interpreted as an integer (bytes are in
+// big-endian order, as 34.10 test vectors have) reduced modulo Q.
+// Zero result is replaced with one, as the standard requires.
+// Streebog's output is little-endian, so it has to be reversed
+// by the caller (see PrivateKeyReverseDigest).
+func (c *Curve) DigestToScalar(digest []byte) *big.Int {
+	return c.modQNonZero(bytes2big(digest))
+}
+
+// Reduce v modulo Q in place, replacing zero with one, as the standard
+// does for the digest scalar e. Signature's r and s components must not
+// be treated that way: zero ones require another nonce.
+func (c *Curve) modQNonZero(v *big.Int) *big.Int {
+	v.Mod(v, c.Q)
+	if v.Sign() == 0 {
+		v.SetInt64(1)
+	}
+	return v
+}
+
+// Compute public key's point d*basic point for the private scalar d,
+// that must be within [1, Q). It is useful for checking (d, Qx, Qy)
+// test vectors without constructing the keys.
+func (c *Curve) PublicPoint(d *big.Int) (x, y *big.Int, err error) {
+	if d.Sign() <= 0 || d.Cmp(c.Q) >= 0 {
+		return nil, nil, errors.New("gogost/gost3410.Curve.PublicPoint: d is out of range")
+	}
+	return c.ScalarBaseMult(d)
+}
+
+// Generate uniformly random element of the Q-order subgroup: basic
+// point multiplied by the random scalar within [1, Q). The scalar is
+// rejection sampled from rand, so there is no modulo bias.
+func (c *Curve) RandomPoint(rand io.Reader) (x, y *big.Int, err error) {
+	raw := make([]byte, (c.Q.BitLen()+7)/8)
+	mask := byte(0xFF >> (uint(len(raw)*8 - c.Q.BitLen())))
+	d := big.NewInt(0)
+	defer zeroize(d)
+	for {
+		if _, err = io.ReadFull(rand, raw); err != nil {
+			return nil, nil, fmt.Errorf("gogost/gost3410.Curve.RandomPoint: %w", err)
+		}
+		raw[0] &= mask
+		d.SetBytes(raw)
+		if d.Sign() > 0 && d.Cmp(c.Q) < 0 {
+			break
+		}
+	}
+	zeroizeBytes(raw)
+	x, y, err = c.ScalarBaseMult(d)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.Curve.RandomPoint: %w", err)
+	}
+	return x, y, nil
+}
+
+// Compute r = (k*basic point).X mod Q, the first component of the
+// signature made with SignWithK and the same k. k must be within
+// [1, Q); k giving zero r is rejected, as no signature can use it.
+func (c *Curve) NonceCommitment(k *big.Int) (r *big.Int, err error) {
+	if k.Sign() <= 0 || k.Cmp(c.Q) >= 0 {
+		return nil, errors.New("gogost/gost3410.Curve.NonceCommitment: k is out of range")
+	}
+	r, _, err = c.ScalarBaseMult(k)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.Curve.NonceCommitment: %w", err)
+	}
+	r.Mod(r, c.Q)
+	if r.Sign() == 0 {
+		return nil, errors.New("gogost/gost3410.Curve.NonceCommitment: r is zero")
+	}
+	return r, nil
+}
+
 // Get the size of the point's coordinate in bytes.
 // 32 for 256-bit curves, 64 for 512-bit ones.
 func (c *Curve) PointSize() int {
 	return pointSize(c.P)
 }
 
+// Get the size of raw signature in bytes: two coordinate sized halves.
+func (c *Curve) SignatureSize() int {
+	return 2 * c.PointSize()
+}
+
+// Get the size of the Streebog digest to be signed in bytes.
+func (c *Curve) DigestSize() int {
+	return c.PointSize()
+}
+
+// Check that the digest has DigestSize length: Streebog-256 digest
+// with 512-bit curve (and vice versa) gives non-interoperable signatures.
+func (c *Curve) checkDigest(digest []byte) error {
+	if len(digest) != c.DigestSize() {
+		return fmt.Errorf(
+			"%w: len(digest)=%d != %d for %d-bit curve",
+			ErrDigestSize, len(digest), c.DigestSize(), c.BitSize(),
+		)
+	}
+	return nil
+}
+
+// Get the curve's size in bits: either 256 or 512.
+func (c *Curve) BitSize() int {
+	return 8 * c.PointSize()
+}
+
+// Is it 512-bit curve.
+func (c *Curve) Is512() bool {
+	return c.BitSize() == 512
+}
+
 func (c *Curve) pos(v *big.Int) {
 	if v.Cmp(zero) < 0 {
 		v.Add(v, c.P)
@@ -136,10 +382,39 @@ func (c *Curve) add(p1x, p1y, p2x, p2y *big.Int) {
 	p1y.Set(&ty)
 }
 
+// Multiply the point by degree. By default fixed-width Jacobian
+// arithmetic is used, then twisted Edwards form if curve has it, as its
+// complete addition law is free of special cases. TuneExp may select
+// faster one for the current machine.
 func (c *Curve) Exp(degree, xS, yS *big.Int) (*big.Int, *big.Int, error) {
+	return c.exp(c.expImpl.Load(), degree, xS, yS)
+}
+
+func (c *Curve) exp(impl int32, degree, xS, yS *big.Int) (*big.Int, *big.Int, error) {
 	if degree.Cmp(zero) == 0 {
 		return nil, nil, errors.New("gogost/gost3410: zero degree value")
 	}
+	if f, a, ok := c.fieldCtx(); ok && degree.Sign() > 0 &&
+		(impl == expAuto || impl == expJacobian) {
+		x, y, isInfinity := c.expJacobian(f, a, degree, xS, yS)
+		if isInfinity {
+			return nil, nil, errors.New("gogost/gost3410: result is at infinity")
+		}
+		return x, y, nil
+	}
+	if c.IsEdwards() && impl != expAffine {
+		x, y, isInfinity, ok := c.expEdwards(degree, xS, yS)
+		if ok {
+			if isInfinity {
+				return nil, nil, errors.New("gogost/gost3410: result is at infinity")
+			}
+			return x, y, nil
+		}
+	}
+	return c.expWeierstrass(degree, xS, yS)
+}
+
+func (c *Curve) expWeierstrass(degree, xS, yS *big.Int) (*big.Int, *big.Int, error) {
 	dg := big.NewInt(0).Sub(degree, bigInt1)
 	tx := big.NewInt(0).Set(xS)
 	ty := big.NewInt(0).Set(yS)
@@ -155,18 +430,167 @@ func (c *Curve) Exp(degree, xS, yS *big.Int) (*big.Int, *big.Int, error) {
 	return tx, ty, nil
 }
 
+// Add two points, either of which can be the point at infinity.
+// Result is placed in p1 and true is returned if it is the infinity.
+func (c *Curve) addInf(p1x, p1y *big.Int, p1Inf bool, p2x, p2y *big.Int, p2Inf bool) bool {
+	if p2Inf {
+		return p1Inf
+	}
+	if p1Inf {
+		p1x.Set(p2x)
+		p1y.Set(p2y)
+		return false
+	}
+	if p1x.Cmp(p2x) == 0 {
+		var t big.Int
+		t.Add(p1y, p2y)
+		t.Mod(&t, c.P)
+		if t.Sign() == 0 {
+			return true
+		}
+	}
+	c.add(p1x, p1y, p2x, p2y)
+	return false
+}
+
+// Multiply the point by the degree. Unlike Exp, it handles the point
+// at infinity: if the result is the identity, then isInfinity is true
+// and returned coordinates are nil. Zero degree gives the identity.
+func (c *Curve) ScalarMult(degree, xS, yS *big.Int) (x, y *big.Int, isInfinity bool, err error) {
+	if degree.Sign() < 0 {
+		return nil, nil, false, errors.New("gogost/gost3410: negative degree value")
+	}
+	if f, a, ok := c.fieldCtx(); ok {
+		x, y, isInfinity = c.expJacobian(f, a, degree, xS, yS)
+		return x, y, isInfinity, nil
+	}
+	return c.scalarMultAffine(degree, xS, yS)
+}
+
+func (c *Curve) scalarMultAffine(degree, xS, yS *big.Int) (x, y *big.Int, isInfinity bool, err error) {
+	x = big.NewInt(0)
+	y = big.NewInt(0)
+	isInfinity = true
+	for i := degree.BitLen() - 1; i >= 0; i-- {
+		isInfinity = c.addInf(x, y, isInfinity, x, y, isInfinity)
+		if degree.Bit(i) == 1 {
+			isInfinity = c.addInf(x, y, isInfinity, xS, yS, false)
+		}
+	}
+	if isInfinity {
+		return nil, nil, true, nil
+	}
+	return x, y, false, nil
+}
+
+// Check that the point lies on the curve and has order Q, returning
+// Q. It does not compute arbitrary point's order, that is expensive:
+// points outside of Q-order subgroup (like small-order ones on curves
+// with cofactor) are just reported with an error.
+func (c *Curve) PointOrder(x, y *big.Int) (*big.Int, error) {
+	if !c.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("gogost/gost3410: point is not on the curve%s", c.notOnCurve(x, y))
+	}
+	_, _, inf, err := c.ScalarMult(c.Q, x, y)
+	if err != nil {
+		return nil, err
+	}
+	if !inf {
+		return nil, errors.New("gogost/gost3410: point's order is not Q")
+	}
+	return c.Q, nil
+}
+
+// Get Co^-1 mod Q, computed once. Returned value must not be modified.
+func (c *Curve) coInverse() *big.Int {
+	c.coInvOnce.Do(func() {
+		c.coInv = big.NewInt(0).ModInverse(c.Co, c.Q)
+	})
+	return c.coInv
+}
+
+// Project the point onto Q-order subgroup, removing its small-order
+// component: (Co^-1 mod Q)*Co*point is computed. Unlike plain
+// multiplication by Co, points already within the subgroup are left
+// unchanged. Small-order points give the point at infinity.
+func (c *Curve) ClearCofactor(x, y *big.Int) (rx, ry *big.Int, isInfinity bool, err error) {
+	if !c.IsOnCurve(x, y) {
+		return nil, nil, false, fmt.Errorf("gogost/gost3410: point is not on the curve%s", c.notOnCurve(x, y))
+	}
+	if c.Co.Cmp(bigInt1) == 0 {
+		return big.NewInt(0).Set(x), big.NewInt(0).Set(y), false, nil
+	}
+	coInv := c.coInverse()
+	if coInv == nil {
+		return nil, nil, false, errors.New("gogost/gost3410: cofactor is not invertible modulo Q")
+	}
+	return c.ScalarMult(big.NewInt(0).Mul(c.Co, coInv), x, y)
+}
+
+// Compare affine points, reducing their coordinates modulo P first, so
+// differently produced representations of the same point are equal.
+// The point at infinity is represented either with nil coordinates or
+// with zero ones, like PublicKey.IsIdentity expects.
+func (c *Curve) PointsEqual(x1, y1, x2, y2 *big.Int) bool {
+	inf1 := x1 == nil || y1 == nil || (x1.Sign() == 0 && y1.Sign() == 0)
+	inf2 := x2 == nil || y2 == nil || (x2.Sign() == 0 && y2.Sign() == 0)
+	if inf1 || inf2 {
+		return inf1 && inf2
+	}
+	var a, b big.Int
+	a.Mod(x1, c.P)
+	b.Mod(x2, c.P)
+	if a.Cmp(&b) != 0 {
+		return false
+	}
+	a.Mod(y1, c.P)
+	b.Mod(y2, c.P)
+	return a.Cmp(&b) == 0
+}
+
 func (our *Curve) Equal(their *Curve) bool {
+	return our.equalWeierstrass(their) &&
+		equalOptional(our.E, their.E) &&
+		equalOptional(our.D, their.D)
+}
+
+// Compare curves ignoring optional twisted Edwards parameters.
+func (our *Curve) equalWeierstrass(their *Curve) bool {
 	return our.P.Cmp(their.P) == 0 &&
 		our.Q.Cmp(their.Q) == 0 &&
 		our.A.Cmp(their.A) == 0 &&
 		our.B.Cmp(their.B) == 0 &&
 		our.X.Cmp(their.X) == 0 &&
 		our.Y.Cmp(their.Y) == 0 &&
-		((our.E == nil && their.E == nil) || our.E.Cmp(their.E) == 0) &&
-		((our.D == nil && their.D == nil) || our.D.Cmp(their.D) == 0) &&
 		our.Co.Cmp(their.Co) == 0
 }
 
+func equalOptional(our, their *big.Int) bool {
+	if our == nil || their == nil {
+		return our == nil && their == nil
+	}
+	return our.Cmp(their) == 0
+}
+
+// Get the copy of the curve with the specified name and parameters set
+// OID, used by the registry and SubjectPublicKeyInfo encoding. The curve
+// itself is not changed, so it is safe to use with the shared curves.
+func (c *Curve) WithName(name string, oid asn1.ObjectIdentifier) *Curve {
+	return &Curve{
+		Name: name,
+		OID:  append(asn1.ObjectIdentifier{}, oid...),
+		P:    c.P,
+		Q:    c.Q,
+		Co:   c.Co,
+		A:    c.A,
+		B:    c.B,
+		E:    c.E,
+		D:    c.D,
+		X:    c.X,
+		Y:    c.Y,
+	}
+}
+
 func (c *Curve) String() string {
 	return c.Name
 }
@@ -0,0 +1,503 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+func TestScalarMultByOrder(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdGostR34102001TestParamSet(),
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		x, y, inf, err := c.ScalarMult(c.Q, c.X, c.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !inf || x != nil || y != nil {
+			t.Fatal(c.Name, "Q*P is not at infinity")
+		}
+		degree := big.NewInt(0).Sub(c.Q, bigInt1)
+		x, y, inf, err = c.ScalarMult(degree, c.X, c.Y)
+		if err != nil || inf {
+			t.Fatal(c.Name, "(Q-1)*P is at infinity")
+		}
+		xExp, yExp, err := c.Exp(degree, c.X, c.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x.Cmp(xExp) != 0 || y.Cmp(yExp) != 0 {
+			t.Fatal(c.Name, "ScalarMult differs from Exp")
+		}
+	}
+}
+
+func TestScalarMultZero(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	_, _, inf, err := c.ScalarMult(zero, c.X, c.Y)
+	if err != nil || !inf {
+		t.FailNow()
+	}
+	if _, _, _, err = c.ScalarMult(big.NewInt(-1), c.X, c.Y); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestDigestToScalar(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	if c.DigestToScalar(make([]byte, 32)).Cmp(bigInt1) != 0 {
+		t.FailNow()
+	}
+	if c.DigestToScalar(pad(c.Q.Bytes(), 32)).Cmp(bigInt1) != 0 {
+		t.FailNow()
+	}
+	qPlus2 := big.NewInt(0).Add(c.Q, bigInt2)
+	if c.DigestToScalar(qPlus2.Bytes()).Cmp(bigInt2) != 0 {
+		t.FailNow()
+	}
+	if c.DigestToScalar([]byte{0x01, 0x02}).Cmp(big.NewInt(0x0102)) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestNewCurveInvalidParams(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	for name, args := range map[string][3]*big.Int{
+		"zero Q":            {std.P, big.NewInt(0), nil},
+		"negative Q":        {std.P, big.NewInt(-1), nil},
+		"Q above bound":     {std.P, big.NewInt(0).Lsh(std.P, 1), nil},
+		"order above bound": {std.P, std.Q, bigInt2},
+		"zero Co":           {std.P, std.Q, big.NewInt(0)},
+		"negative Co":       {std.P, std.Q, big.NewInt(-4)},
+	} {
+		_, err := NewCurve(args[0], args[1], std.A, std.B, std.X, std.Y, nil, nil, args[2])
+		if !errors.Is(err, ErrInvalidCurveParams) {
+			t.Fatal(name, err)
+		}
+	}
+	_, err := NewCurve(std.P, std.Q, std.A, std.B, std.X, bigInt1, nil, nil, nil)
+	if !errors.Is(err, ErrInvalidCurveParams) {
+		t.FailNow()
+	}
+	co := CurveIdtc26gost341012256paramSetA()
+	if _, err = NewCurve(co.P, co.Q, co.A, co.B, co.X, co.Y, nil, nil, co.Co); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSingularCurve(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	for name, ab := range map[string][4]*big.Int{
+		// y^2 = x^3, cusp at (0, 0)
+		"cusp": {big.NewInt(0), big.NewInt(0), bigInt1, bigInt1},
+		// y^2 = x^3 - 3x + 2 = (x-1)^2 * (x+2), node at (1, 0)
+		"node": {big.NewInt(0).Sub(std.P, bigInt3), bigInt2, bigInt2, bigInt2},
+	} {
+		_, err := NewCurve(std.P, std.Q, ab[0], ab[1], ab[2], ab[3], nil, nil, nil)
+		if !errors.Is(err, ErrSingularCurve) || !errors.Is(err, ErrInvalidCurveParams) {
+			t.Fatal(name, err)
+		}
+		c := Curve{P: std.P, Q: std.Q, A: ab[0], B: ab[1], X: ab[2], Y: ab[3], Co: bigInt1}
+		if !c.IsOnCurve(c.X, c.Y) {
+			t.Fatal(name, "not on curve")
+		}
+		if err = c.Validate(); !errors.Is(err, ErrSingularCurve) {
+			t.Fatal(name, err)
+		}
+	}
+}
+
+func TestExpEdwardsMatchesWeierstrass(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		f := func(raw [64]byte) bool {
+			degree := big.NewInt(0).SetBytes(raw[:c.PointSize()])
+			if degree.Sign() == 0 {
+				return true
+			}
+			x, y, err := c.Exp(degree, c.X, c.Y)
+			if err != nil {
+				return false
+			}
+			expectedX, expectedY, err := c.expWeierstrass(degree, c.X, c.Y)
+			if err != nil {
+				return false
+			}
+			edX, edY, _, ok := c.expEdwards(degree, c.X, c.Y)
+			if !ok || edX.Cmp(expectedX) != 0 || edY.Cmp(expectedY) != 0 {
+				return false
+			}
+			return x.Cmp(expectedX) == 0 && y.Cmp(expectedY) == 0
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Fatal(c.Name, err)
+		}
+		if _, _, err := c.Exp(c.Q, c.X, c.Y); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestToMontgomery(t *testing.T) {
+	if _, _, ok := CurveIdGostR34102001CryptoProAParamSet().ToMontgomery(); ok {
+		t.FailNow()
+	}
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		a, b, ok := c.ToMontgomery()
+		if !ok {
+			t.Fatal(c.Name)
+		}
+		f := func(raw [64]byte) bool {
+			degree := big.NewInt(0).SetBytes(raw[:c.PointSize()])
+			degree.Mod(degree, c.Q)
+			if degree.Sign() == 0 {
+				return true
+			}
+			px, py, err := c.Exp(degree, c.X, c.Y)
+			if err != nil {
+				return false
+			}
+			u, v := XY2UV(c, px, py)
+			x := big.NewInt(0).Sub(bigInt1, v)
+			c.pos(x)
+			x.ModInverse(x, c.P)
+			x.Mul(x, big.NewInt(0).Add(bigInt1, v))
+			x.Mod(x, c.P)
+			y := big.NewInt(0).ModInverse(u, c.P)
+			y.Mul(y, x)
+			y.Mod(y, c.P)
+			left := big.NewInt(0).Mul(y, y)
+			left.Mul(left, b)
+			left.Mod(left, c.P)
+			right := big.NewInt(0).Add(x, a)
+			right.Mul(right, x)
+			right.Add(right, bigInt1)
+			right.Mul(right, x)
+			right.Mod(right, c.P)
+			return left.Cmp(right) == 0
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Fatal(c.Name, err)
+		}
+	}
+}
+
+func BenchmarkExpWeierstrass(b *testing.B) {
+	c := CurveIdtc26gost341012256paramSetA()
+	degree := big.NewInt(0).Sub(c.Q, bigInt2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.expWeierstrass(degree, c.X, c.Y)
+	}
+}
+
+func BenchmarkExpEdwards(b *testing.B) {
+	c := CurveIdtc26gost341012256paramSetA()
+	degree := big.NewInt(0).Sub(c.Q, bigInt2)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.expEdwards(degree, c.X, c.Y)
+	}
+}
+
+// Project some point of the whole group to the small-order subgroup
+func smallOrderPoint(t *testing.T, c *Curve) (x, y *big.Int) {
+	var inf bool
+	var err error
+	for i := int64(1); ; i++ {
+		x = big.NewInt(i)
+		y2 := big.NewInt(0).Mul(x, x)
+		y2.Add(y2, c.A)
+		y2.Mul(y2, x)
+		y2.Add(y2, c.B)
+		y2.Mod(y2, c.P)
+		y = big.NewInt(0).ModSqrt(y2, c.P)
+		if y == nil {
+			continue
+		}
+		x, y, inf, err = c.ScalarMult(c.Q, x, y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !inf {
+			return x, y
+		}
+	}
+}
+
+func TestPointOrder(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	order, err := c.PointOrder(c.X, c.Y)
+	if err != nil || order.Cmp(c.Q) != 0 {
+		t.FailNow()
+	}
+	x, y := smallOrderPoint(t, c)
+	if _, err = c.PointOrder(x, y); err == nil {
+		t.Fatal("small-order point is accepted")
+	}
+	if _, err = c.PointOrder(c.X, big.NewInt(0).Add(c.Y, bigInt1)); err == nil {
+		t.Fatal("point outside the curve is accepted")
+	}
+}
+
+func TestConcurrentExp(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	degree := big.NewInt(0).Sub(c.Q, bigInt2)
+	expectedX, expectedY, err := c.expWeierstrass(degree, c.X, c.Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	errs := make(chan error, 32)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 0 {
+				c.PrecomputeBase()
+			}
+			var x, y *big.Int
+			var err error
+			for j := 0; j < 8; j++ {
+				if (i+j)%2 == 0 {
+					x, y, err = c.Exp(degree, c.X, c.Y)
+				} else {
+					x, y, err = c.ScalarBaseMult(degree)
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+				if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+					errs <- errors.New("result mismatch")
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestModQNonZero(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(0).Set(c.Q),
+		big.NewInt(0).Lsh(c.Q, 3),
+	} {
+		if c.modQNonZero(v).Cmp(bigInt1) != 0 {
+			t.Fatal(v)
+		}
+	}
+	v := big.NewInt(0).Add(c.Q, big.NewInt(5))
+	if c.modQNonZero(v).Int64() != 5 || v.Int64() != 5 {
+		t.FailNow()
+	}
+	if c.DigestToScalar(pad(c.Q.Bytes(), c.PointSize())).Cmp(bigInt1) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestClearCofactor(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	if c.Co.Int64() != 4 {
+		t.FailNow()
+	}
+	one := big.NewInt(0).Mul(c.coInverse(), c.Co)
+	if one.Mod(one, c.Q).Cmp(bigInt1) != 0 {
+		t.Fatal("invalid inverse")
+	}
+	x, y, inf, err := c.ClearCofactor(c.X, c.Y)
+	if err != nil || inf || x.Cmp(c.X) != 0 || y.Cmp(c.Y) != 0 {
+		t.Fatal("subgroup point is changed")
+	}
+	tx, ty := smallOrderPoint(t, c)
+	if _, _, inf, err = c.ClearCofactor(tx, ty); err != nil || !inf {
+		t.Fatal("small-order point is not cleared")
+	}
+	px, py := big.NewInt(0).Set(c.X), big.NewInt(0).Set(c.Y)
+	c.add(px, py, tx, ty)
+	x, y, inf, err = c.ClearCofactor(px, py)
+	if err != nil || inf || x.Cmp(c.X) != 0 || y.Cmp(c.Y) != 0 {
+		t.Fatal("small-order component is not removed")
+	}
+	if _, _, _, err = c.ClearCofactor(bigInt1, bigInt1); err == nil {
+		t.Fatal("point off the curve is accepted")
+	}
+	c = CurveIdtc26gost341012256paramSetB()
+	if c.coInverse().Cmp(bigInt1) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestPointsEqual(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	x2, y2, err := c.ScalarBaseMult(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.PointsEqual(c.X, c.Y, c.X, c.Y) || c.PointsEqual(c.X, c.Y, x2, y2) {
+		t.FailNow()
+	}
+	shiftedX := big.NewInt(0).Add(c.X, c.P)
+	negativeY := big.NewInt(0).Sub(c.Y, big.NewInt(0).Lsh(c.P, 1))
+	if !c.PointsEqual(c.X, c.Y, shiftedX, negativeY) {
+		t.Fatal("unnormalized coordinates are not equal")
+	}
+	if c.PointsEqual(c.X, c.Y, c.X, big.NewInt(0).Sub(c.P, c.Y)) {
+		t.Fatal("negated point is equal")
+	}
+	zero := big.NewInt(0)
+	if !c.PointsEqual(nil, nil, zero, zero) || !c.PointsEqual(nil, nil, nil, nil) {
+		t.Fatal("identity representations are not equal")
+	}
+	if c.PointsEqual(nil, nil, c.X, c.Y) || c.PointsEqual(c.X, c.Y, zero, zero) {
+		t.Fatal("identity is equal to the point")
+	}
+}
+
+func TestSignatureDigestSize(t *testing.T) {
+	for _, c := range RegisteredCurves() {
+		want := 64
+		if c.Is512() {
+			want = 128
+		}
+		if c.SignatureSize() != want {
+			t.Fatal(c.Name, c.SignatureSize())
+		}
+		if len(rsToSignature(c, big.NewInt(1), big.NewInt(1))) != c.SignatureSize() {
+			t.Fatal(c.Name)
+		}
+		h, err := gost34112012256.NewHashBySize(c.BitSize())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Size() != c.DigestSize() {
+			t.Fatal(c.Name, c.DigestSize())
+		}
+	}
+}
+
+// Public key from GOST R 34.10-2012 appendix A.1 example
+func TestPublicPoint(t *testing.T) {
+	c := CurveIdGostR34102001TestParamSet()
+	d := bytes2big([]byte{
+		0x7A, 0x92, 0x9A, 0xDE, 0x78, 0x9B, 0xB9, 0xBE,
+		0x10, 0xED, 0x35, 0x9D, 0xD3, 0x9A, 0x72, 0xC1,
+		0x1B, 0x60, 0x96, 0x1F, 0x49, 0x39, 0x7E, 0xEE,
+		0x1D, 0x19, 0xCE, 0x98, 0x91, 0xEC, 0x3B, 0x28,
+	})
+	x, y, err := c.PublicPoint(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.Cmp(bytes2big([]byte{
+		0x7F, 0x2B, 0x49, 0xE2, 0x70, 0xDB, 0x6D, 0x90,
+		0xD8, 0x59, 0x5B, 0xEC, 0x45, 0x8B, 0x50, 0xC5,
+		0x85, 0x85, 0xBA, 0x1D, 0x4E, 0x9B, 0x78, 0x8F,
+		0x66, 0x89, 0xDB, 0xD8, 0xE5, 0x6F, 0xD8, 0x0B,
+	})) != 0 {
+		t.FailNow()
+	}
+	if y.Cmp(bytes2big([]byte{
+		0x26, 0xF1, 0xB4, 0x89, 0xD6, 0x70, 0x1D, 0xD1,
+		0x85, 0xC8, 0x41, 0x3A, 0x97, 0x7B, 0x3C, 0xBB,
+		0xAF, 0x64, 0xD1, 0xC5, 0x93, 0xD2, 0x66, 0x27,
+		0xDF, 0xFB, 0x10, 0x1A, 0x87, 0xFF, 0x77, 0xDA,
+	})) != 0 {
+		t.FailNow()
+	}
+	for _, d := range []*big.Int{big.NewInt(0), big.NewInt(-1), c.Q} {
+		if _, _, err = c.PublicPoint(d); err == nil {
+			t.Fatal(d)
+		}
+	}
+}
+
+func TestPointsWithX(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		p1, p2, ok := c.PointsWithX(c.X)
+		if !ok {
+			t.Fatal(c.Name)
+		}
+		if !c.IsOnCurve(p1[0], p1[1]) || !c.IsOnCurve(p2[0], p2[1]) {
+			t.Fatal(c.Name, "not on curve")
+		}
+		if p1[1].Cmp(c.Y) != 0 && p2[1].Cmp(c.Y) != 0 {
+			t.Fatal(c.Name, "basic point is not found")
+		}
+		if p1[1].Cmp(p2[1]) == 0 {
+			t.Fatal(c.Name, "the same points")
+		}
+		var found, missing bool
+		for x := big.NewInt(1); !(found && missing); x.Add(x, bigInt1) {
+			p1, p2, ok = c.PointsWithX(x)
+			if !ok {
+				missing = true
+				continue
+			}
+			found = true
+			if !c.IsOnCurve(p1[0], p1[1]) || !c.IsOnCurve(p2[0], p2[1]) {
+				t.Fatal(c.Name, x)
+			}
+		}
+		if _, _, ok = c.PointsWithX(c.P); ok {
+			t.Fatal(c.Name, "x out of range accepted")
+		}
+	}
+}
+
+func TestRandomPoint(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		for i := 0; i < 8; i++ {
+			x, y, err := c.RandomPoint(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !c.IsOnCurve(x, y) {
+				t.Fatal("point is not on the curve")
+			}
+			if _, _, inf, err := c.ScalarMult(c.Q, x, y); err != nil || !inf {
+				t.Fatal("point is not of Q order", err)
+			}
+		}
+	}
+}
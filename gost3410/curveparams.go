@@ -0,0 +1,93 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Explicit curve parameters, GostR3410-2001-ParamSetParameters
+// from RFC 4357, also used in TC26 containers.
+type curveParams struct {
+	A *big.Int
+	B *big.Int
+	P *big.Int
+	Q *big.Int
+	X *big.Int
+	Y *big.Int
+}
+
+// Parse explicit DER-encoded curve parameters: SEQUENCE of a, b, p,
+// q, x, y INTEGERs. Cofactor is not carried, so it is derived as the
+// nearest integer to (p+1)/q, that is unique for curves with
+// q > 4*sqrt(p) due to the Hasse bound. Resulting curve is validated
+// with Curve.Validate.
+func ParseCurveParams(der []byte) (*Curve, error) {
+	var params curveParams
+	rest, err := asn1.Unmarshal(der, &params)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseCurveParams: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParseCurveParams: trailing data")
+	}
+	if params.P.Sign() <= 0 || params.Q.Sign() <= 0 {
+		return nil, fmt.Errorf("gogost/gost3410.ParseCurveParams: %w", ErrInvalidCurveParams)
+	}
+	co := big.NewInt(0).Rsh(params.Q, 1)
+	co.Add(co, params.P)
+	co.Add(co, bigInt1)
+	co.Div(co, params.Q)
+	if co.Sign() == 0 {
+		co.SetInt64(1)
+	}
+	c, err := NewCurve(
+		params.P, params.Q, params.A, params.B,
+		params.X, params.Y, nil, nil, co,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseCurveParams: %w", err)
+	}
+	if err = c.Validate(); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseCurveParams: %w", err)
+	}
+	return c, nil
+}
+
+// Parse explicit curve parameters like ParseCurveParams does, but
+// accept only the ones equal to one of the allowed curves, protecting
+// against attacker-chosen curves substitution. If allowed is nil, then
+// all registered curves are allowed. Twisted Edwards parameters are not
+// carried, so they are not compared. The matching allowed curve itself
+// is returned, with its name and OID.
+func ParseCurveParamsAllowed(der []byte, allowed []*Curve) (*Curve, error) {
+	c, err := ParseCurveParams(der)
+	if err != nil {
+		return nil, err
+	}
+	if allowed == nil {
+		allowed = RegisteredCurves()
+	}
+	for _, a := range allowed {
+		if a.equalWeierstrass(c) {
+			return a, nil
+		}
+	}
+	return nil, errors.New("gogost/gost3410.ParseCurveParamsAllowed: curve is not allowed")
+}
@@ -0,0 +1,93 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestParseCurveParams(t *testing.T) {
+	// id-GostR3410-2001-CryptoPro-A-ParamSet explicit parameters
+	der, _ := hex.DecodeString("308193" +
+		"022100fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd94" +
+		"020200a6" +
+		"022100fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffd97" +
+		"022100ffffffffffffffffffffffffffffffff6c611070995ad10045841b09b761b893" +
+		"020101" +
+		"0221008d91e471e0989cda27df505a453f2b7635294f2ddf23e3b122acc99c9e9f1e14")
+	std := CurveIdGostR34102001CryptoProAParamSet()
+	c, err := ParseCurveParams(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Equal(std) {
+		t.FailNow()
+	}
+	cofactored := CurveIdtc26gost341012256paramSetA()
+	der, _ = asn1.Marshal(curveParams{
+		cofactored.A, cofactored.B, cofactored.P,
+		cofactored.Q, cofactored.X, cofactored.Y,
+	})
+	if c, err = ParseCurveParams(der); err != nil {
+		t.Fatal(err)
+	}
+	if c.Co.Cmp(cofactored.Co) != 0 {
+		t.FailNow()
+	}
+	der, _ = asn1.Marshal(curveParams{std.A, std.B, std.P, std.Q, std.X, std.X})
+	if _, err = ParseCurveParams(der); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestParseCurveParamsAllowed(t *testing.T) {
+	cofactored := CurveIdtc26gost341012256paramSetA()
+	der, _ := asn1.Marshal(curveParams{
+		cofactored.A, cofactored.B, cofactored.P,
+		cofactored.Q, cofactored.X, cofactored.Y,
+	})
+	c, err := ParseCurveParamsAllowed(der, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != cofactored.Name || !c.Equal(cofactored) {
+		t.Fatal("registered curve is not returned")
+	}
+	if _, err = ParseCurveParamsAllowed(der, []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+	}); err == nil {
+		t.Fatal("not allowed curve is accepted")
+	}
+
+	// Valid, but non-standard curve: the same one with another basic point
+	x, y, err := cofactored.ScalarBaseMult(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ = asn1.Marshal(curveParams{
+		cofactored.A, cofactored.B, cofactored.P,
+		cofactored.Q, x, y,
+	})
+	if _, err = ParseCurveParams(der); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseCurveParamsAllowed(der, nil); err == nil {
+		t.Fatal("non-standard curve is accepted")
+	}
+}
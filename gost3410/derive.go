@@ -0,0 +1,64 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+)
+
+// Minimal parent seed length accepted by DeriveChild.
+const DeriveChildMinSeedLen = 16
+
+// Deterministically derive the child private key with the given index
+// from the parent seed. Candidate scalars are HMAC-Streebog-512 over
+// "gogost/gost3410 child" || BE32(index) || BE32(counter) keyed with
+// the seed, truncated to Q's bit length, and the first one in [1, Q)
+// is taken, so the key is uniformly distributed. The same seed, index
+// and curve always give the same key.
+func DeriveChild(parentSeed []byte, index uint32, c *Curve) (*PrivateKey, error) {
+	if len(parentSeed) < DeriveChildMinSeedLen {
+		return nil, errors.New("gogost/gost3410.DeriveChild: too short seed")
+	}
+	bitLen := c.Q.BitLen()
+	if bitLen > 8*gost34112012512.Size {
+		return nil, errors.New("gogost/gost3410.DeriveChild: too large Q")
+	}
+	mac := hmac.New(gost34112012512.New, parentSeed)
+	var buf [8]byte
+	binary.BigEndian.PutUint32(buf[:4], index)
+	mask := big.NewInt(0).Lsh(bigInt1, uint(bitLen))
+	mask.Sub(mask, bigInt1)
+	k := big.NewInt(0)
+	for ctr := uint32(0); ; ctr++ {
+		binary.BigEndian.PutUint32(buf[4:], ctr)
+		mac.Reset()
+		mac.Write([]byte("gogost/gost3410 child"))
+		mac.Write(buf[:])
+		k.SetBytes(mac.Sum(nil)[:(bitLen+7)/8])
+		k.And(k, mask)
+		if k.Sign() > 0 && k.Cmp(c.Q) < 0 {
+			return &PrivateKey{C: c, Key: k}, nil
+		}
+	}
+}
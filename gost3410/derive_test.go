@@ -0,0 +1,81 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDeriveChildDeterministic(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x5A}, 32)
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		for _, index := range []uint32{0, 1, 1 << 31} {
+			prv1, err := DeriveChild(seed, index, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prv2, err := DeriveChild(append([]byte{}, seed...), index, c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prv1.Key.Cmp(prv2.Key) != 0 {
+				t.FailNow()
+			}
+			if prv1.Key.Sign() <= 0 || prv1.Key.Cmp(c.Q) >= 0 {
+				t.FailNow()
+			}
+			if _, err = prv1.PublicKey(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if _, err := DeriveChild(seed[:DeriveChildMinSeedLen-1], 0, CurveIdtc26gost341012256paramSetB()); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestDeriveChildDistinct(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	seed := bytes.Repeat([]byte{0xA5}, 32)
+	seen := make(map[string]uint32)
+	for index := uint32(0); index < 256; index++ {
+		prv, err := DeriveChild(seed, index, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := string(prv.Raw())
+		if prev, ok := seen[key]; ok {
+			t.Fatal("collision", prev, index)
+		}
+		seen[key] = index
+	}
+	otherSeed := bytes.Repeat([]byte{0xA6}, 32)
+	prv, err := DeriveChild(otherSeed, 0, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := seen[string(prv.Raw())]; ok {
+		t.Fatal("different seeds give the same key")
+	}
+}
@@ -2,4 +2,8 @@
 // GOST R 34.10-2012 (RFC 7091) signature algorithms and
 // VKO GOST R 34.10-2001 (RFC 4357),
 // VKO GOST R 34.10-2012 (RFC 7836) key agreement algorithms.
+//
+// Building with the gogost_verifyonly tag leaves only curve arithmetic,
+// public key parsing and signature verification: private keys, signing,
+// key generation and VKO are excluded.
 package gost3410
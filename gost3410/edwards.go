@@ -24,26 +24,165 @@ func (c *Curve) IsEdwards() bool {
 }
 
 func (c *Curve) EdwardsST() (*big.Int, *big.Int) {
-	if c.edS != nil {
-		return c.edS, c.edT
+	c.edOnce.Do(func() {
+		edS := big.NewInt(0)
+		edS.Set(c.E)
+		edS.Sub(edS, c.D)
+		c.pos(edS)
+		var t big.Int
+		t.SetUint64(4)
+		t.ModInverse(&t, c.P)
+		edS.Mul(edS, &t)
+		edS.Mod(edS, c.P)
+		edT := big.NewInt(0)
+		edT.Set(c.E)
+		edT.Add(edT, c.D)
+		t.SetUint64(6)
+		t.ModInverse(&t, c.P)
+		edT.Mul(edT, &t)
+		edT.Mod(edT, c.P)
+		c.edT = edT
+		c.edS = edS
+	})
+	return c.edS, c.edT
+}
+
+// Montgomery form B*y^2 = x^3 + A*x^2 + x parameters of the curve,
+// birationally equivalent to its twisted Edwards form:
+// A = 2(e+d)/(e-d), B = 4/(e-d). The map is x = (1+v)/(1-v), y = x/u.
+// ok is false if curve has no twisted Edwards form.
+func (c *Curve) ToMontgomery() (A, B *big.Int, ok bool) {
+	if !c.IsEdwards() {
+		return nil, nil, false
+	}
+	inv := big.NewInt(0).Sub(c.E, c.D)
+	c.pos(inv)
+	if inv.ModInverse(inv, c.P) == nil {
+		return nil, nil, false
+	}
+	A = big.NewInt(0).Add(c.E, c.D)
+	A.Lsh(A, 1)
+	A.Mul(A, inv)
+	A.Mod(A, c.P)
+	B = big.NewInt(0).Lsh(inv, 2)
+	B.Mod(B, c.P)
+	return A, B, true
+}
+
+// Twisted Edwards point in extended projective coordinates:
+// u = X/Z, v = Y/Z, T = X*Y/Z.
+type edPoint struct {
+	x, y, t, z *big.Int
+}
+
+// Add q to p in place with unified add-2008-hwcd formulae, that are
+// also used for doubling. They are complete, when e is a square and
+// d is a non-square, as for all GOST twisted Edwards curves.
+func (c *Curve) edAdd(p, q *edPoint) {
+	a := big.NewInt(0).Mul(p.x, q.x)
+	a.Mod(a, c.P)
+	b := big.NewInt(0).Mul(p.y, q.y)
+	b.Mod(b, c.P)
+	cc := big.NewInt(0).Mul(p.t, q.t)
+	cc.Mod(cc, c.P)
+	cc.Mul(cc, c.D)
+	cc.Mod(cc, c.P)
+	d := big.NewInt(0).Mul(p.z, q.z)
+	d.Mod(d, c.P)
+	e := big.NewInt(0).Add(p.x, p.y)
+	t := big.NewInt(0).Add(q.x, q.y)
+	e.Mul(e, t)
+	e.Sub(e, a)
+	e.Sub(e, b)
+	e.Mod(e, c.P)
+	f := t.Sub(d, cc)
+	f.Mod(f, c.P)
+	g := d.Add(d, cc)
+	g.Mod(g, c.P)
+	h := cc.Mul(a, c.E)
+	h.Sub(b, h)
+	h.Mod(h, c.P)
+	p.x.Mul(e, f)
+	p.x.Mod(p.x, c.P)
+	p.y.Mul(g, h)
+	p.y.Mod(p.y, c.P)
+	p.t.Mul(e, h)
+	p.t.Mod(p.t, c.P)
+	p.z.Mul(f, g)
+	p.z.Mod(p.z, c.P)
+}
+
+// Double p in place with dbl-2008-hwcd formulae.
+func (c *Curve) edDouble(p *edPoint) {
+	a := big.NewInt(0).Mul(p.x, p.x)
+	a.Mod(a, c.P)
+	b := big.NewInt(0).Mul(p.y, p.y)
+	b.Mod(b, c.P)
+	cc := big.NewInt(0).Mul(p.z, p.z)
+	cc.Lsh(cc, 1)
+	cc.Mod(cc, c.P)
+	e := big.NewInt(0).Add(p.x, p.y)
+	e.Mul(e, e)
+	e.Sub(e, a)
+	e.Sub(e, b)
+	e.Mod(e, c.P)
+	d := a.Mul(a, c.E)
+	d.Mod(d, c.P)
+	g := big.NewInt(0).Add(d, b)
+	f := cc.Sub(g, cc)
+	f.Mod(f, c.P)
+	h := d.Sub(d, b)
+	h.Mod(h, c.P)
+	g.Mod(g, c.P)
+	p.x.Mul(e, f)
+	p.x.Mod(p.x, c.P)
+	p.y.Mul(g, h)
+	p.y.Mod(p.y, c.P)
+	p.t.Mul(e, h)
+	p.t.Mod(p.t, c.P)
+	p.z.Mul(f, g)
+	p.z.Mod(p.z, c.P)
+}
+
+// Multiply Weierstrass point by non-negative degree in twisted Edwards
+// form. ok is false if the point or result have no twisted Edwards
+// representation (points of order 2) and the caller has to fall back
+// to Weierstrass arithmetic.
+func (c *Curve) expEdwards(degree, xS, yS *big.Int) (x, y *big.Int, isInfinity, ok bool) {
+	if degree.Sign() < 0 || yS.Sign() == 0 {
+		return
 	}
-	c.edS = big.NewInt(0)
-	c.edS.Set(c.E)
-	c.edS.Sub(c.edS, c.D)
-	c.pos(c.edS)
+	edS, edT := c.EdwardsST()
 	var t big.Int
-	t.SetUint64(4)
-	t.ModInverse(&t, c.P)
-	c.edS.Mul(c.edS, &t)
-	c.edS.Mod(c.edS, c.P)
-	c.edT = big.NewInt(0)
-	c.edT.Set(c.E)
-	c.edT.Add(c.edT, c.D)
-	t.SetUint64(6)
-	t.ModInverse(&t, c.P)
-	c.edT.Mul(c.edT, &t)
-	c.edT.Mod(c.edT, c.P)
-	return c.edS, c.edT
+	t.Sub(xS, edT)
+	t.Add(&t, edS)
+	t.Mod(&t, c.P)
+	if t.Sign() == 0 {
+		return
+	}
+	u, v := XY2UV(c, xS, yS)
+	pnt := &edPoint{u, v, big.NewInt(0).Mul(u, v), big.NewInt(1)}
+	pnt.t.Mod(pnt.t, c.P)
+	r := &edPoint{big.NewInt(0), big.NewInt(1), big.NewInt(0), big.NewInt(1)}
+	for i := degree.BitLen() - 1; i >= 0; i-- {
+		c.edDouble(r)
+		if degree.Bit(i) == 1 {
+			c.edAdd(r, pnt)
+		}
+	}
+	zInv := big.NewInt(0).ModInverse(r.z, c.P)
+	u = r.x.Mul(r.x, zInv)
+	u.Mod(u, c.P)
+	v = r.y.Mul(r.y, zInv)
+	v.Mod(v, c.P)
+	if u.Sign() == 0 {
+		if v.Cmp(bigInt1) == 0 {
+			return nil, nil, true, true
+		}
+		return
+	}
+	x, y = UV2XY(c, u, v)
+	return x, y, false, true
 }
 
 // Convert Weierstrass X,Y coordinates to twisted Edwards U,V
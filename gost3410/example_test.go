@@ -0,0 +1,58 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410_test
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hitchpock/gogost/v5/gost3410"
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+func Example() {
+	// DeterministicReader is used only to make the example reproducible.
+	// Use crypto/rand.Reader in real code!
+	rand := gost3410.DeterministicReader([]byte("example"))
+	curve := gost3410.CurveIdtc26gost341012256paramSetB()
+	prv, err := gost3410.GenPrivateKey(curve, rand)
+	if err != nil {
+		panic(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		panic(err)
+	}
+	hasher := gost34112012256.New()
+	hasher.Write([]byte("data to be signed"))
+	dgst := hasher.Sum(nil)
+	sign, err := prv.Sign(rand, dgst, nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(hex.EncodeToString(sign[:8]))
+	valid, err := pub.VerifyDigest(dgst, sign)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(valid)
+	// Output:
+	// f39ab7b000e30ca0
+	// true
+}
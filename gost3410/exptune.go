@@ -0,0 +1,76 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"math/big"
+	"time"
+)
+
+// Exp implementations
+const (
+	expAuto int32 = iota
+	expJacobian
+	expEdwards
+	expAffine
+)
+
+// Number of multiplications each Exp implementation is timed on
+const tuneExpRounds = 4
+
+// Measure Exp implementations available for the curve (fixed-width
+// Jacobian, twisted Edwards, affine Weierstrass) on the current machine
+// and select the fastest one as c's Exp default, used also by
+// signature verification and ScalarBaseMult. Choice is stored in c
+// only: NewCurve and every CurveId*() call create separate curve, so
+// tuning is the explicit opt-in of its owner, not affecting other
+// users of the same parameters. Curve.WithName copy is not tuned. It
+// is safe to call concurrently with other curve's methods. All
+// implementations give the same results and all of them are
+// variable-time: there is no constant-time one to keep secret scalars
+// on. ScalarMult, used by signing, is not affected.
+func (c *Curve) TuneExp() {
+	candidates := []int32{expAffine}
+	if _, _, ok := c.fieldCtx(); ok {
+		candidates = append(candidates, expJacobian)
+	}
+	if c.IsEdwards() {
+		candidates = append(candidates, expEdwards)
+	}
+	degree := big.NewInt(0).Sub(c.Q, bigInt1)
+	best, bestTime := expAuto, time.Duration(0)
+	for _, impl := range candidates {
+		elapsed, ok := c.timeExp(impl, degree)
+		if ok && (best == expAuto || elapsed < bestTime) {
+			best, bestTime = impl, elapsed
+		}
+	}
+	c.expImpl.Store(best)
+}
+
+// Get the best of tuneExpRounds timings of the Exp implementation.
+func (c *Curve) timeExp(impl int32, degree *big.Int) (best time.Duration, ok bool) {
+	for i := 0; i < tuneExpRounds; i++ {
+		start := time.Now()
+		if _, _, err := c.exp(impl, degree, c.X, c.Y); err != nil {
+			return 0, false
+		}
+		if elapsed := time.Since(start); i == 0 || elapsed < best {
+			best = elapsed
+		}
+	}
+	return best, true
+}
@@ -0,0 +1,80 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestTuneExp(t *testing.T) {
+	for _, newCurve := range []func() *Curve{
+		CurveIdtc26gost341012256paramSetA,
+		CurveIdtc26gost341012512paramSetC,
+		CurveIdGostR34102001TestParamSet,
+	} {
+		c := newCurve()
+		degree := big.NewInt(123456789)
+		x, y, err := c.Exp(degree, c.X, c.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.DigestSize())
+		rand.Read(digest)
+		sign, err := prv.SignDigest(digest, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check := func(what interface{}) {
+			gotX, gotY, err := c.Exp(degree, c.X, c.Y)
+			if err != nil {
+				t.Fatal(c.Name, what, err)
+			}
+			if gotX.Cmp(x) != 0 || gotY.Cmp(y) != 0 {
+				t.Fatal(c.Name, "Exp differs", what)
+			}
+			if valid, err := pub.VerifyDigest(digest, sign); err != nil || !valid {
+				t.Fatal(c.Name, "verification fails", what, err)
+			}
+		}
+		for _, impl := range []int32{expJacobian, expEdwards, expAffine} {
+			c.expImpl.Store(impl)
+			check(impl)
+		}
+		c.expImpl.Store(expAuto)
+		c.TuneExp()
+		impl := c.expImpl.Load()
+		if impl == expAuto {
+			t.Fatal(c.Name, "implementation is not selected")
+		}
+		check("tuned")
+		if newCurve().expImpl.Load() != expAuto || c.WithName("copy", nil).expImpl.Load() != expAuto {
+			t.Fatal(c.Name, "tuning is not limited to the curve")
+		}
+	}
+}
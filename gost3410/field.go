@@ -0,0 +1,315 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+)
+
+const feMaxLimbs = 8
+
+// Fixed-width prime field element in Montgomery form: little-endian
+// 64-bit limbs, only field.n first of them are used.
+type fe [feMaxLimbs]uint64
+
+// Montgomery arithmetic modulo odd prime up to 512 bits.
+type field struct {
+	n    int    // Number of used limbs
+	p    fe     // Modulus
+	pInv uint64 // -p^-1 mod 2^64
+	r2   fe     // R^2 mod p, R = 2^(64n), not in Montgomery form
+	one  fe     // 1 in Montgomery form
+}
+
+func newField(p *big.Int) *field {
+	if p.Bit(0) == 0 || p.BitLen() > 64*feMaxLimbs {
+		return nil
+	}
+	f := field{n: (p.BitLen() + 63) / 64}
+	f.p = f.rawFromBig(p)
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - f.p[0]*inv
+	}
+	f.pInv = -inv
+	r := big.NewInt(0).Lsh(bigInt1, uint(64*f.n))
+	f.one = f.rawFromBig(big.NewInt(0).Mod(r, p))
+	r.Mul(r, r)
+	f.r2 = f.rawFromBig(r.Mod(r, p))
+	return &f
+}
+
+func (f *field) rawFromBig(v *big.Int) (z fe) {
+	var buf [8 * feMaxLimbs]byte
+	v.FillBytes(buf[:])
+	for i := 0; i < feMaxLimbs; i++ {
+		z[i] = binary.BigEndian.Uint64(buf[len(buf)-8*(i+1):])
+	}
+	return
+}
+
+// Convert v from [0, p) into Montgomery form.
+func (f *field) fromBig(v *big.Int) (z fe) {
+	z = f.rawFromBig(v)
+	f.mul(&z, &z, &f.r2)
+	return
+}
+
+func (f *field) toBig(x *fe) *big.Int {
+	var one fe
+	one[0] = 1
+	var z fe
+	f.mul(&z, x, &one)
+	return f.toBigRaw(&z)
+}
+
+func (f *field) toBigRaw(z *fe) *big.Int {
+	var buf [8 * feMaxLimbs]byte
+	for i := 0; i < feMaxLimbs; i++ {
+		binary.BigEndian.PutUint64(buf[len(buf)-8*(i+1):], z[i])
+	}
+	return big.NewInt(0).SetBytes(buf[:])
+}
+
+// z = x*y/R mod p, CIOS method.
+func (f *field) mul(z, x, y *fe) {
+	var t [feMaxLimbs + 2]uint64
+	n := f.n
+	for i := 0; i < n; i++ {
+		var c, hi, lo uint64
+		for j := 0; j < n; j++ {
+			hi, lo = bits.Mul64(x[j], y[i])
+			lo, c = bits.Add64(lo, c, 0)
+			hi += c
+			t[j], c = bits.Add64(t[j], lo, 0)
+			c += hi
+		}
+		t[n], c = bits.Add64(t[n], c, 0)
+		t[n+1] = c
+		m := t[0] * f.pInv
+		hi, lo = bits.Mul64(m, f.p[0])
+		_, c = bits.Add64(t[0], lo, 0)
+		c += hi
+		for j := 1; j < n; j++ {
+			hi, lo = bits.Mul64(m, f.p[j])
+			lo, cc := bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], cc = bits.Add64(t[j], lo, 0)
+			c = hi + cc
+		}
+		t[n-1], c = bits.Add64(t[n], c, 0)
+		t[n] = t[n+1] + c
+	}
+	f.reduce(z, t[:n], t[n])
+}
+
+// z = t mod p, where t = carry*R + t < 2p. Choice between t and t-p is
+// made without branching.
+func (f *field) reduce(z *fe, t []uint64, carry uint64) {
+	var r fe
+	var b uint64
+	for i := 0; i < f.n; i++ {
+		r[i], b = bits.Sub64(t[i], f.p[i], b)
+	}
+	// keep t only if carry == 0 and b == 1
+	mask := -(b &^ carry)
+	for i := 0; i < f.n; i++ {
+		z[i] = (t[i] & mask) | (r[i] &^ mask)
+	}
+	for i := f.n; i < feMaxLimbs; i++ {
+		z[i] = 0
+	}
+}
+
+func (f *field) add(z, x, y *fe) {
+	var t [feMaxLimbs]uint64
+	var c uint64
+	for i := 0; i < f.n; i++ {
+		t[i], c = bits.Add64(x[i], y[i], c)
+	}
+	f.reduce(z, t[:f.n], c)
+}
+
+func (f *field) sub(z, x, y *fe) {
+	var b uint64
+	for i := 0; i < f.n; i++ {
+		z[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	mask := -b
+	var c uint64
+	for i := 0; i < f.n; i++ {
+		z[i], c = bits.Add64(z[i], f.p[i]&mask, c)
+	}
+}
+
+// z = x^(p-2) = x^-1 mod p by Fermat's little theorem, p must be prime.
+// Exponent is public, so timing does not depend on the x value. Zero
+// is mapped to zero.
+func (f *field) inv(z, x *fe) {
+	e := f.toBigRaw(&f.p)
+	e.Sub(e, big.NewInt(2))
+	r := f.one
+	base := *x
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		f.mul(&r, &r, &r)
+		if e.Bit(i) == 1 {
+			f.mul(&r, &r, &base)
+		}
+	}
+	*z = r
+}
+
+func (f *field) isZero(x *fe) bool {
+	var acc uint64
+	for i := 0; i < f.n; i++ {
+		acc |= x[i]
+	}
+	return acc == 0
+}
+
+// Point in Jacobian coordinates: x = X/Z^2, y = Y/Z^3.
+type jacPoint struct {
+	x, y, z fe
+}
+
+// Double p in place with dbl-2007-bl formulae, returning true if result
+// is at infinity.
+func (c *Curve) jacDouble(f *field, a *fe, p *jacPoint) bool {
+	var xx, yy, yyyy, zz, s, m, t fe
+	f.mul(&xx, &p.x, &p.x)
+	f.mul(&yy, &p.y, &p.y)
+	f.mul(&yyyy, &yy, &yy)
+	f.mul(&zz, &p.z, &p.z)
+	f.add(&s, &p.x, &yy)
+	f.mul(&s, &s, &s)
+	f.sub(&s, &s, &xx)
+	f.sub(&s, &s, &yyyy)
+	f.add(&s, &s, &s)
+	f.mul(&m, &zz, &zz)
+	f.mul(&m, &m, a)
+	f.add(&m, &m, &xx)
+	f.add(&m, &m, &xx)
+	f.add(&m, &m, &xx)
+	f.add(&p.z, &p.y, &p.z)
+	f.mul(&p.z, &p.z, &p.z)
+	f.sub(&p.z, &p.z, &yy)
+	f.sub(&p.z, &p.z, &zz)
+	f.mul(&t, &m, &m)
+	f.sub(&t, &t, &s)
+	f.sub(&t, &t, &s)
+	p.x = t
+	f.sub(&s, &s, &t)
+	f.mul(&p.y, &m, &s)
+	f.add(&yyyy, &yyyy, &yyyy)
+	f.add(&yyyy, &yyyy, &yyyy)
+	f.add(&yyyy, &yyyy, &yyyy)
+	f.sub(&p.y, &p.y, &yyyy)
+	return f.isZero(&p.z)
+}
+
+// Add affine (x2, y2) to p in place with madd-2007-bl formulae,
+// returning true if result is at infinity.
+func (c *Curve) jacAddAffine(f *field, a *fe, p *jacPoint, x2, y2 *fe) bool {
+	var z1z1, u2, s2, h, hh, i, j, r, v fe
+	f.mul(&z1z1, &p.z, &p.z)
+	f.mul(&u2, x2, &z1z1)
+	f.mul(&s2, y2, &p.z)
+	f.mul(&s2, &s2, &z1z1)
+	f.sub(&h, &u2, &p.x)
+	f.sub(&r, &s2, &p.y)
+	if f.isZero(&h) {
+		if f.isZero(&r) {
+			return c.jacDouble(f, a, p)
+		}
+		return true
+	}
+	f.add(&r, &r, &r)
+	f.mul(&hh, &h, &h)
+	f.add(&i, &hh, &hh)
+	f.add(&i, &i, &i)
+	f.mul(&j, &h, &i)
+	f.mul(&v, &p.x, &i)
+	f.add(&p.z, &p.z, &h)
+	f.mul(&p.z, &p.z, &p.z)
+	f.sub(&p.z, &p.z, &z1z1)
+	f.sub(&p.z, &p.z, &hh)
+	f.mul(&p.x, &r, &r)
+	f.sub(&p.x, &p.x, &j)
+	f.sub(&p.x, &p.x, &v)
+	f.sub(&p.x, &p.x, &v)
+	f.mul(&j, &j, &p.y)
+	f.add(&j, &j, &j)
+	f.sub(&v, &v, &p.x)
+	f.mul(&p.y, &r, &v)
+	f.sub(&p.y, &p.y, &j)
+	return false
+}
+
+// Field and A coefficient in Montgomery form, lazily created. ok is
+// false if P is not suitable for the fixed-width arithmetic.
+func (c *Curve) fieldCtx() (f *field, a *fe, ok bool) {
+	c.fieldOnce.Do(func() {
+		c.fld = newField(c.P)
+		if c.fld != nil {
+			aMod := big.NewInt(0).Mod(c.A, c.P)
+			c.fldA = c.fld.fromBig(aMod)
+		}
+	})
+	return c.fld, &c.fldA, c.fld != nil
+}
+
+// Multiply the point by non-negative degree with fixed-width
+// arithmetic in Jacobian coordinates.
+func (c *Curve) expJacobian(f *field, a *fe, degree, xS, yS *big.Int) (x, y *big.Int, isInfinity bool) {
+	px := f.fromBig(big.NewInt(0).Mod(xS, c.P))
+	py := f.fromBig(big.NewInt(0).Mod(yS, c.P))
+	var q jacPoint
+	isInfinity = true
+	for i := degree.BitLen() - 1; i >= 0; i-- {
+		if !isInfinity {
+			isInfinity = c.jacDouble(f, a, &q)
+		}
+		if degree.Bit(i) == 0 {
+			continue
+		}
+		if isInfinity {
+			q = jacPoint{x: px, y: py, z: f.one}
+			isInfinity = false
+		} else {
+			isInfinity = c.jacAddAffine(f, a, &q, &px, &py)
+		}
+	}
+	if isInfinity {
+		return nil, nil, true
+	}
+	x, y = c.jacToAffine(f, &q)
+	return x, y, false
+}
+
+// Convert point (not at infinity) to affine coordinates.
+func (c *Curve) jacToAffine(f *field, q *jacPoint) (x, y *big.Int) {
+	zInv := f.toBig(&q.z)
+	zInv.ModInverse(zInv, c.P)
+	z := f.fromBig(zInv)
+	var zz, qx, qy fe
+	f.mul(&zz, &z, &z)
+	f.mul(&qx, &q.x, &zz)
+	f.mul(&zz, &zz, &z)
+	f.mul(&qy, &q.y, &zz)
+	return f.toBig(&qx), f.toBig(&qy)
+}
@@ -0,0 +1,128 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestFieldCrossBig(t *testing.T) {
+	for _, c := range RegisteredCurves() {
+		f, _, ok := c.fieldCtx()
+		if !ok {
+			t.Fatal(c.Name)
+		}
+		edge := []*big.Int{
+			big.NewInt(0),
+			big.NewInt(1),
+			big.NewInt(2),
+			big.NewInt(0).Sub(c.P, bigInt1),
+			big.NewInt(0).Sub(c.P, bigInt2),
+			big.NewInt(0).Rsh(c.P, 1),
+		}
+		vals := append([]*big.Int{}, edge...)
+		for i := 0; i < 64; i++ {
+			v, err := rand.Int(rand.Reader, c.P)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vals = append(vals, v)
+		}
+		var z fe
+		expected := big.NewInt(0)
+		for _, x := range vals {
+			fx := f.fromBig(x)
+			if f.toBig(&fx).Cmp(x) != 0 {
+				t.Fatal(c.Name, "conversion", x)
+			}
+			for _, y := range vals {
+				fy := f.fromBig(y)
+				f.mul(&z, &fx, &fy)
+				expected.Mul(x, y).Mod(expected, c.P)
+				if f.toBig(&z).Cmp(expected) != 0 {
+					t.Fatal(c.Name, "mul", x, y)
+				}
+				f.add(&z, &fx, &fy)
+				expected.Add(x, y).Mod(expected, c.P)
+				if f.toBig(&z).Cmp(expected) != 0 {
+					t.Fatal(c.Name, "add", x, y)
+				}
+				f.sub(&z, &fx, &fy)
+				expected.Sub(x, y).Mod(expected, c.P)
+				if f.toBig(&z).Cmp(expected) != 0 {
+					t.Fatal(c.Name, "sub", x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestExpJacobianCrossBig(t *testing.T) {
+	for _, c := range RegisteredCurves() {
+		f, a, _ := c.fieldCtx()
+		degrees := []*big.Int{
+			big.NewInt(1),
+			big.NewInt(2),
+			big.NewInt(3),
+			big.NewInt(0).Sub(c.Q, bigInt1),
+			big.NewInt(0).Add(c.Q, bigInt1),
+		}
+		for i := 0; i < 16; i++ {
+			d, err := rand.Int(rand.Reader, c.Q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			degrees = append(degrees, d.Add(d, bigInt1))
+		}
+		for _, d := range degrees {
+			x, y, inf := c.expJacobian(f, a, d, c.X, c.Y)
+			if inf {
+				t.Fatal(c.Name, d)
+			}
+			expectedX, expectedY, _, err := c.scalarMultAffine(d, c.X, c.Y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if x.Cmp(expectedX) != 0 || y.Cmp(expectedY) != 0 {
+				t.Fatal(c.Name, d)
+			}
+		}
+		if _, _, inf := c.expJacobian(f, a, c.Q, c.X, c.Y); !inf {
+			t.Fatal(c.Name)
+		}
+	}
+}
+
+func BenchmarkExpJacobian(b *testing.B) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		degree := big.NewInt(0).Sub(c.Q, bigInt2)
+		b.Run(c.Name+"/jacobian", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.Exp(degree, c.X, c.Y)
+			}
+		})
+		b.Run(c.Name+"/big", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c.expWeierstrass(degree, c.X, c.Y)
+			}
+		})
+	}
+}
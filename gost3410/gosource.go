@@ -0,0 +1,61 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Generate Go source defining varName variable with the curve, created
+// with NewCurve from hexadecimal parameters, including its name and
+// OID. It is intended for embedding curves without the registry: the
+// file using it has to import "encoding/asn1", "math/big" and gost3410.
+func (c *Curve) GoSource(varName string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s curve\n", c.Name)
+	fmt.Fprintf(&b, "var %s = func() *gost3410.Curve {\n", varName)
+	b.WriteString("\th := func(s string) *big.Int {\n")
+	b.WriteString("\t\tv, ok := big.NewInt(0).SetString(s, 16)\n")
+	b.WriteString("\t\tif !ok {\n\t\t\tpanic(\"invalid curve parameter\")\n\t\t}\n")
+	b.WriteString("\t\treturn v\n\t}\n")
+	b.WriteString("\tcurve, err := gost3410.NewCurve(\n")
+	for _, p := range []struct {
+		name string
+		v    *big.Int
+	}{
+		{"P", c.P}, {"Q", c.Q}, {"A", c.A}, {"B", c.B}, {"X", c.X}, {"Y", c.Y},
+		{"E", c.E}, {"D", c.D}, {"Co", c.Co},
+	} {
+		if p.v == nil {
+			fmt.Fprintf(&b, "\t\tnil, // %s\n", p.name)
+		} else {
+			fmt.Fprintf(&b, "\t\th(\"%x\"), // %s\n", p.v, p.name)
+		}
+	}
+	b.WriteString("\t)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	fmt.Fprintf(&b, "\tcurve.Name = %q\n", c.Name)
+	if len(c.OID) > 0 {
+		arcs := make([]string, 0, len(c.OID))
+		for _, arc := range c.OID {
+			arcs = append(arcs, fmt.Sprint(arc))
+		}
+		fmt.Fprintf(&b, "\tcurve.OID = asn1.ObjectIdentifier{%s}\n", strings.Join(arcs, ", "))
+	}
+	b.WriteString("\treturn curve\n}()\n")
+	return b.String()
+}
@@ -0,0 +1,85 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math/big"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCurveGoSource(t *testing.T) {
+	paramRe := regexp.MustCompile(`(h\("([0-9a-f]+)"\)|nil), // ([A-Za-z]+)`)
+	fset := token.NewFileSet()
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	for _, c := range RegisteredCurves() {
+		src := c.GoSource("curveVar")
+		if !strings.Contains(src, `curve.Name = "`+c.Name+`"`) {
+			t.Fatal(c.Name, "no name")
+		}
+		imports := "\t\"math/big\"\n\n\t\"github.com/hitchpock/gogost/v5/gost3410\"\n"
+		if len(c.OID) > 0 {
+			imports = "\t\"encoding/asn1\"\n" + imports
+		}
+		f, err := parser.ParseFile(
+			fset, "curve.go", "package p\n\nimport (\n"+imports+")\n\n"+src,
+			parser.AllErrors,
+		)
+		if err != nil {
+			t.Fatal(c.Name, err)
+		}
+		pkg, err := conf.Check("p", fset, []*ast.File{f}, nil)
+		if err != nil {
+			t.Fatal(c.Name, err)
+		}
+		typ := pkg.Scope().Lookup("curveVar").Type().String()
+		if typ != "*github.com/hitchpock/gogost/v5/gost3410.Curve" {
+			t.Fatal(c.Name, typ)
+		}
+		params := make(map[string]*big.Int)
+		matches := paramRe.FindAllStringSubmatch(src, -1)
+		if len(matches) != 9 {
+			t.Fatal(c.Name, len(matches))
+		}
+		for _, m := range matches {
+			if m[1] == "nil" {
+				params[m[3]] = nil
+				continue
+			}
+			v, ok := big.NewInt(0).SetString(m[2], 16)
+			if !ok {
+				t.Fatal(c.Name, m[2])
+			}
+			params[m[3]] = v
+		}
+		got, err := NewCurve(
+			params["P"], params["Q"], params["A"], params["B"],
+			params["X"], params["Y"], params["E"], params["D"], params["Co"],
+		)
+		if err != nil {
+			t.Fatal(c.Name, err)
+		}
+		if !got.Equal(c) {
+			t.Fatal(c.Name, "differs")
+		}
+	}
+}
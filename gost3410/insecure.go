@@ -0,0 +1,64 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+type deterministicReader struct {
+	seed []byte
+	h    hash.Hash
+	ctr  uint64
+	buf  []byte
+}
+
+// INSECURE! NEVER USE IT FOR REAL KEYS OR SIGNATURES!
+//
+// Deterministic reader producing Streebog-256(seed || BE64(counter))
+// blocks stream. It is intended only for reproducible tests and
+// examples: signatures made with predictable nonce reveal the private key.
+func DeterministicReader(seed []byte) io.Reader {
+	return &deterministicReader{
+		seed: append([]byte{}, seed...),
+		h:    gost34112012256.New(),
+	}
+}
+
+func (r *deterministicReader) Read(p []byte) (n int, err error) {
+	var ctr [8]byte
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			binary.BigEndian.PutUint64(ctr[:], r.ctr)
+			r.ctr++
+			r.h.Reset()
+			r.h.Write(r.seed)
+			r.h.Write(ctr[:])
+			r.buf = r.h.Sum(r.buf[:0])
+		}
+		c := copy(p[n:], r.buf)
+		r.buf = r.buf[c:]
+		n += c
+	}
+	return n, nil
+}
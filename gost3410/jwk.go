@@ -0,0 +1,143 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JWK key type of GOST R 34.10 keys, GoGOST-specific.
+const JWKKeyType = "GOST"
+
+// JWK-like representation of GOST keys. It is GoGOST's own
+// non-standard format: no registered JOSE key type for GOST R 34.10
+// exists and it is not based on any draft, so interoperability with
+// other implementations is not expected. "kty" is JWKKeyType, "crv" is
+// the curve's name, as CurveByName accepts it. "x", "y" and optional
+// private "d" are base64url encoded without padding, as JOSE requires.
+// Like RFC 7518 EC keys, they are big-endian and padded to the curve's
+// PointSize, unlike little-endian raw GOST keys encoding.
+type jwk struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	D   string `json:"d,omitempty"`
+}
+
+func jwkDecode(c *Curve, name, v string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.Strict().DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %q: %w", name, err)
+	}
+	if len(raw) != c.PointSize() {
+		return nil, fmt.Errorf("invalid %q length %d != %d", name, len(raw), c.PointSize())
+	}
+	return bytes2big(raw), nil
+}
+
+func jwkEncode(c *Curve, v *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(pad(v.Bytes(), c.PointSize()))
+}
+
+// Parse GoGOST-specific JWK-like JSON object (see MarshalJWK) into
+// either *PublicKey, or *PrivateKey if "d" is present. Public key must
+// lie on the curve and it must correspond to the private one.
+func ParseJWK(data []byte) (interface{}, error) {
+	var j jwk
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: %w", err)
+	}
+	if j.Kty != JWKKeyType {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: unsupported key type %q", j.Kty)
+	}
+	c := CurveByName(j.Crv)
+	if c == nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: unknown curve %q", j.Crv)
+	}
+	x, err := jwkDecode(c, "x", j.X)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: %w", err)
+	}
+	y, err := jwkDecode(c, "y", j.Y)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: %w", err)
+	}
+	pub := &PublicKey{C: c, X: x, Y: y}
+	if !c.IsOnCurve(x, y) {
+		return nil, errors.New("gogost/gost3410.ParseJWK: public key is not on the curve")
+	}
+	if j.D == "" {
+		return pub, nil
+	}
+	d, err := jwkDecode(c, "d", j.D)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: %w", err)
+	}
+	if d.Sign() == 0 || d.Cmp(c.Q) >= 0 {
+		return nil, errors.New("gogost/gost3410.ParseJWK: private key is out of [1, Q) range")
+	}
+	prv := &PrivateKey{C: c, Key: d}
+	ourPub, err := prv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseJWK: %w", err)
+	}
+	if !ourPub.Equal(pub) {
+		return nil, errors.New("gogost/gost3410.ParseJWK: public key does not match the private one")
+	}
+	return prv, nil
+}
+
+// Marshal either *PublicKey or *PrivateKey into GoGOST-specific
+// JWK-like JSON object. Curve must be registered, as ParseJWK finds it
+// by the name.
+func MarshalJWK(key interface{}) ([]byte, error) {
+	var pub *PublicKey
+	var d string
+	switch k := key.(type) {
+	case *PublicKey:
+		pub = k
+	case *PrivateKey:
+		var err error
+		pub, err = k.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("gogost/gost3410.MarshalJWK: %w", err)
+		}
+		d = jwkEncode(k.C, k.Key)
+	default:
+		return nil, fmt.Errorf("gogost/gost3410.MarshalJWK: unsupported key type %T", key)
+	}
+	if pub.IsIdentity() {
+		return nil, errors.New("gogost/gost3410.MarshalJWK: key is the identity")
+	}
+	if c := CurveByName(pub.C.Name); c == nil || !c.Equal(pub.C) {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalJWK: unknown curve %q", pub.C.Name)
+	}
+	return json.Marshal(jwk{
+		Kty: JWKKeyType,
+		Crv: pub.C.Name,
+		X:   jwkEncode(pub.C, pub.X),
+		Y:   jwkEncode(pub.C, pub.Y),
+		D:   d,
+	})
+}
@@ -0,0 +1,135 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJWKRoundTrip(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := MarshalJWK(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(data, []byte(`"d"`)) || bytes.Contains(data, []byte("=")) {
+			t.Fatal(string(data))
+		}
+		key, err := ParseJWK(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotPub, ok := key.(*PublicKey); !ok || !gotPub.Equal(pub) {
+			t.Fatal(c.Name, "public key differs")
+		}
+
+		if data, err = MarshalJWK(prv); err != nil {
+			t.Fatal(err)
+		}
+		if key, err = ParseJWK(data); err != nil {
+			t.Fatal(err)
+		}
+		if gotPrv, ok := key.(*PrivateKey); !ok || gotPrv.Key.Cmp(prv.Key) != 0 || !gotPrv.C.Equal(c) {
+			t.Fatal(c.Name, "private key differs")
+		}
+
+		// Coordinates are big-endian, unlike raw encoding
+		var j jwk
+		if err = json.Unmarshal(data, &j); err != nil {
+			t.Fatal(err)
+		}
+		x, _ := base64.RawURLEncoding.DecodeString(j.X)
+		raw := pub.Raw()
+		reverse(x)
+		if !bytes.Equal(x, raw[:c.PointSize()]) {
+			t.Fatal(c.Name, "x is not big-endian")
+		}
+	}
+}
+
+func TestJWKInvalid(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalJWK(prv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var j jwk
+	if err = json.Unmarshal(data, &j); err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, mutate := range map[string]func(j *jwk){
+		"kty":     func(j *jwk) { j.Kty = "EC" },
+		"crv":     func(j *jwk) { j.Crv = "P-256" },
+		"padding": func(j *jwk) { j.X = base64.URLEncoding.EncodeToString(pad(c.X.Bytes(), 32)) },
+		"short x": func(j *jwk) { j.X = j.X[:len(j.X)-2] },
+		"off":     func(j *jwk) { j.Y = j.X },
+		"d":       func(j *jwk) { j.D = jwkEncode(c, other.Key) },
+		"zero d":  func(j *jwk) { j.D = jwkEncode(c, zero) },
+		"big d":   func(j *jwk) { j.D = jwkEncode(c, c.Q) },
+	} {
+		bad := j
+		mutate(&bad)
+		data, err := json.Marshal(bad)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = ParseJWK(data); err == nil {
+			t.Fatal(name, "accepted")
+		}
+	}
+	// RFC 7836 long curve names are also accepted
+	long := j
+	long.Crv = strings.Replace(j.Crv, "-12-", "-2012-", 1)
+	data, err = json.Marshal(long)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = ParseJWK(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = MarshalJWK("key"); err == nil {
+		t.FailNow()
+	}
+}
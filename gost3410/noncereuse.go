@@ -0,0 +1,61 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Recover private key from two signatures of different digests made
+// with the same nonce k, which is noticeable by equal r values.
+// s1 - s2 = k*(e1 - e2), so k and then d = (s1 - k*e1) / r are found.
+// It is a demonstration of why nonce reuse is fatal and an audit tool.
+func RecoverKeyFromReusedNonce(c *Curve, digest1, sig1, digest2, sig2 []byte) (*PrivateKey, error) {
+	r1, s1, err := SignatureToRS(c, sig1)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverKeyFromReusedNonce: %w", err)
+	}
+	r2, s2, err := SignatureToRS(c, sig2)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverKeyFromReusedNonce: %w", err)
+	}
+	if r1.Cmp(r2) != 0 {
+		return nil, errors.New("gogost/gost3410.RecoverKeyFromReusedNonce: r values differ, nonce is not reused")
+	}
+	e1 := c.DigestToScalar(digest1)
+	e2 := c.DigestToScalar(digest2)
+	de := big.NewInt(0).Sub(e1, e2)
+	de.Mod(de, c.Q)
+	if de.Sign() == 0 {
+		return nil, errors.New("gogost/gost3410.RecoverKeyFromReusedNonce: digests are equal modulo Q")
+	}
+	k := big.NewInt(0).Sub(s1, s2)
+	k.Mul(k, de.ModInverse(de, c.Q))
+	k.Mod(k, c.Q)
+	d := big.NewInt(0).Mul(k, e1)
+	d.Sub(s1, d)
+	d.Mul(d, big.NewInt(0).ModInverse(r1, c.Q))
+	d.Mod(d, c.Q)
+	if d.Sign() == 0 {
+		return nil, errors.New("gogost/gost3410.RecoverKeyFromReusedNonce: zero private key")
+	}
+	return &PrivateKey{C: c, Key: d}, nil
+}
@@ -0,0 +1,56 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import "encoding/asn1"
+
+var (
+	// Public key algorithms
+	oidGostR34102001     = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 19}
+	oidTc26Gost341012256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 1}
+	oidTc26Gost341012512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 1, 2}
+
+	// Digest algorithms
+	oidGostR341194CryptoProParamSet = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 30, 1}
+	oidTc26Gost341112256            = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 2}
+	oidTc26Gost341112512            = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 2, 3}
+
+	// Signature algorithms
+	oidGostR341194WithGostR34102001    = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 3}
+	oidTc26SignWithDigestGost341012256 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 3, 2}
+	oidTc26SignWithDigestGost341012512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 3, 3}
+
+	// HMAC algorithms
+	oidTc26HMACGost341112512 = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 4, 2}
+
+	// Encryption algorithms
+	oidTc26CipherKuznyechikMGM = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 1, 5, 2, 3}
+
+	// RFC 8018 PKCS #5
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	// RFC 5652 CMS content types
+	oidCMSData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidCMSSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+
+	// CryptoPro parameter sets prefix, requiring digestParamSet
+	oidCryptoProParamSetPrefix = asn1.ObjectIdentifier{1, 2, 643, 2, 2}
+)
+
+func oidHasPrefix(oid, prefix asn1.ObjectIdentifier) bool {
+	return len(oid) >= len(prefix) && prefix.Equal(oid[:len(prefix)])
+}
@@ -0,0 +1,79 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const ownershipLabel = "gogost/gost3410 ownership"
+
+// Compute Schnorr challenge scalar e = H(label || curve || pub || R ||
+// BE64(len(challenge)) || challenge) mod Q, H being curveHash.
+func ownershipScalar(pub *PublicKey, rx, ry *big.Int, challenge []byte) *big.Int {
+	c := pub.C
+	h := curveHash(c)
+	h.Write([]byte(ownershipLabel))
+	pointSize := c.PointSize()
+	for _, v := range []*big.Int{c.P, c.Q, c.A, c.B, c.X, c.Y} {
+		h.Write(pad(v.Bytes(), pointSize))
+	}
+	h.Write(pub.Raw())
+	h.Write((&PublicKey{C: c, X: rx, Y: ry}).Raw())
+	l := make([]byte, 8)
+	binary.BigEndian.PutUint64(l, uint64(len(challenge)))
+	h.Write(l)
+	h.Write(challenge)
+	e := bytes2big(h.Sum(nil))
+	return e.Mod(e, c.Q)
+}
+
+// Verify the proof of private key possession, made by ProveOwnership
+// for the same challenge. As s*P = R + e*Pub, R is recomputed and must
+// give the same e.
+func (pub *PublicKey) VerifyOwnership(challenge, proof []byte) (bool, error) {
+	c := pub.C
+	pointSize := c.PointSize()
+	if len(proof) != 2*pointSize {
+		return false, fmt.Errorf("gogost/gost3410.PublicKey.VerifyOwnership: len(proof)=%d != %d", len(proof), 2*pointSize)
+	}
+	if pub.IsIdentity() || !c.IsOnCurve(pub.X, pub.Y) {
+		return false, errors.New("gogost/gost3410.PublicKey.VerifyOwnership: key is not on the curve")
+	}
+	e := bytes2big(proof[:pointSize])
+	s := bytes2big(proof[pointSize:])
+	if e.Sign() <= 0 || e.Cmp(c.Q) >= 0 || s.Sign() <= 0 || s.Cmp(c.Q) >= 0 {
+		return false, nil
+	}
+	sx, sy, err := c.ScalarBaseMult(s)
+	if err != nil {
+		return false, fmt.Errorf("gogost/gost3410.PublicKey.VerifyOwnership: %w", err)
+	}
+	rx, ry, inf, err := c.ScalarMult(big.NewInt(0).Sub(c.Q, e), pub.X, pub.Y)
+	if err != nil {
+		return false, fmt.Errorf("gogost/gost3410.PublicKey.VerifyOwnership: %w", err)
+	}
+	if inf {
+		rx, ry = big.NewInt(0), big.NewInt(0)
+	}
+	if c.addInf(rx, ry, inf, sx, sy, false) {
+		return false, nil
+	}
+	return ownershipScalar(pub, rx, ry, challenge).Cmp(e) == 0, nil
+}
@@ -0,0 +1,65 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestOwnership(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		proof, err := prv.ProveOwnership(rand.Reader, []byte("session 1"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := pub.VerifyOwnership([]byte("session 1"), proof); err != nil || !ok {
+			t.Fatal("valid proof is rejected", err)
+		}
+		if ok, _ := pub.VerifyOwnership([]byte("session 2"), proof); ok {
+			t.Fatal("proof is accepted for another challenge")
+		}
+		other, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherPub, err := other.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, _ := otherPub.VerifyOwnership([]byte("session 1"), proof); ok {
+			t.Fatal("proof is accepted for another key")
+		}
+		proof[len(proof)-1] ^= 1
+		if ok, _ := pub.VerifyOwnership([]byte("session 1"), proof); ok {
+			t.Fatal("altered proof is accepted")
+		}
+	}
+}
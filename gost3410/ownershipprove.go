@@ -0,0 +1,68 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Prove possession of the private key with Schnorr proof bound to the
+// challenge, that must be unique per session (for example verifier's
+// random nonce) to prevent proofs replay. Random k gives R = k*P,
+// e = H(label, curve, pub, R, challenge) mod Q and s = k + e*d mod Q.
+// Proof is BE(e)||BE(s), checked with PublicKey.VerifyOwnership.
+func (prv *PrivateKey) ProveOwnership(rand io.Reader, challenge []byte) (proof []byte, err error) {
+	c := prv.C
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ProveOwnership: %w", err)
+	}
+	kRaw := make([]byte, c.PointSize())
+	k := big.NewInt(0)
+	s := big.NewInt(0)
+	defer zeroizeBytes(kRaw)
+	defer zeroize(k)
+	var rx, ry, e *big.Int
+Retry:
+	if _, err = io.ReadFull(rand, kRaw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ProveOwnership: %w", err)
+	}
+	k.SetBytes(kRaw)
+	k.Mod(k, c.Q)
+	if k.Sign() == 0 {
+		goto Retry
+	}
+	rx, ry, err = c.ScalarBaseMult(k)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.ProveOwnership: %w", err)
+	}
+	e = ownershipScalar(pub, rx, ry, challenge)
+	if e.Sign() == 0 {
+		goto Retry
+	}
+	s.Mul(e, prv.Key)
+	s.Add(s, k)
+	s.Mod(s, c.Q)
+	if s.Sign() == 0 {
+		goto Retry
+	}
+	return append(pad(e.Bytes(), c.PointSize()), pad(s.Bytes(), c.PointSize())...), nil
+}
@@ -15,7 +15,10 @@
 
 package gost3410
 
-import "math/big"
+import (
+	"encoding/asn1"
+	"math/big"
+)
 
 var (
 	CurveGostR34102001ParamSetcc func() *Curve = func() *Curve {
@@ -64,6 +67,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "GostR34102001ParamSetcc"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 9, 1, 8, 1}
 		return curve
 	}
 	// id-GostR3410-2001-TestParamSet
@@ -113,6 +117,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-GostR3410-2001-TestParamSet"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 0}
 		return curve
 	}
 	// id-tc26-gost-3410-12-256-paramSetA
@@ -167,6 +172,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-256-paramSetA"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 1}
 		return curve
 	}
 	// id-tc26-gost-3410-12-256-paramSetB
@@ -216,6 +222,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-256-paramSetB"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 2}
 		return curve
 	}
 	// id-tc26-gost-3410-12-256-paramSetC
@@ -265,6 +272,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-256-paramSetC"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 3}
 		return curve
 	}
 	// id-tc26-gost-3410-12-256-paramSetD
@@ -314,6 +322,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-256-paramSetD"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 1, 4}
 		return curve
 	}
 	// id-tc26-gost-3410-12-512-paramSetTest
@@ -378,6 +387,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-512-paramSetTest"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 0}
 		return curve
 	}
 	// id-tc26-gost-3410-12-512-paramSetA
@@ -451,6 +461,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-512-paramSetA"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 1}
 		return curve
 	}
 	// id-tc26-gost-3410-12-512-paramSetB
@@ -524,6 +535,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-512-paramSetB"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 2}
 		return curve
 	}
 	// id-tc26-gost-3410-12-512-paramSetC
@@ -606,6 +618,7 @@ var (
 			panic(err)
 		}
 		curve.Name = "id-tc26-gost-3410-12-512-paramSetC"
+		curve.OID = asn1.ObjectIdentifier{1, 2, 643, 7, 1, 2, 1, 2, 3}
 		return curve
 	}
 
@@ -615,30 +628,35 @@ var (
 	CurveIdGostR34102001CryptoProAParamSet func() *Curve = func() *Curve {
 		c := CurveIdtc26gost341012256paramSetB()
 		c.Name = "id-GostR3410-2001-CryptoPro-A-ParamSet"
+		c.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 1}
 		return c
 	}
 	// id-GostR3410-2001-CryptoPro-B-ParamSet
 	CurveIdGostR34102001CryptoProBParamSet func() *Curve = func() *Curve {
 		c := CurveIdtc26gost341012256paramSetC()
 		c.Name = "id-GostR3410-2001-CryptoPro-B-ParamSet"
+		c.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 2}
 		return c
 	}
 	// id-GostR3410-2001-CryptoPro-C-ParamSet
 	CurveIdGostR34102001CryptoProCParamSet func() *Curve = func() *Curve {
 		c := CurveIdtc26gost341012256paramSetD()
 		c.Name = "id-GostR3410-2001-CryptoPro-C-ParamSet"
+		c.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 35, 3}
 		return c
 	}
 	// id-GostR3410-2001-CryptoPro-XchA-ParamSet
 	CurveIdGostR34102001CryptoProXchAParamSet func() *Curve = func() *Curve {
 		c := CurveIdGostR34102001CryptoProAParamSet()
 		c.Name = "id-GostR3410-2001-CryptoPro-XchA-ParamSet"
+		c.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 0}
 		return c
 	}
 	// id-GostR3410-2001-CryptoPro-XchB-ParamSet
 	CurveIdGostR34102001CryptoProXchBParamSet func() *Curve = func() *Curve {
 		c := CurveIdGostR34102001CryptoProCParamSet()
 		c.Name = "id-GostR3410-2001-CryptoPro-XchB-ParamSet"
+		c.OID = asn1.ObjectIdentifier{1, 2, 643, 2, 2, 36, 1}
 		return c
 	}
 	// id-tc26-gost-3410-2012-256-paramSetA
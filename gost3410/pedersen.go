@@ -0,0 +1,101 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+)
+
+const pedersenLabel = "gogost/gost3410 pedersen H"
+
+// Get the second generator H of Q-order subgroup, nobody knows the
+// discrete logarithm of. X coordinate candidates are Streebog-512 of
+// the label, curve parameters and 32-bit big-endian counter, reduced
+// modulo P. The first candidate lying on the curve, taken with even Y
+// and multiplied by the cofactor, is H. It is cheap compared to the
+// point multiplication, so is not cached.
+func (c *Curve) pedersenH() (x, y *big.Int) {
+	h := gost34112012512.New()
+	ctr := make([]byte, 4)
+	for i := uint32(0); ; i++ {
+		h.Reset()
+		h.Write([]byte(pedersenLabel))
+		for _, v := range []*big.Int{c.P, c.Q, c.A, c.B, c.X, c.Y} {
+			h.Write(v.Bytes())
+		}
+		binary.BigEndian.PutUint32(ctr, i)
+		h.Write(ctr)
+		hx := bytes2big(h.Sum(nil))
+		hx.Mod(hx, c.P)
+		p1, p2, ok := c.PointsWithX(hx)
+		if !ok {
+			continue
+		}
+		if p1[1].Bit(0) != 0 {
+			p1 = p2
+		}
+		hx, hy, inf, err := c.ScalarMult(c.Co, p1[0], p1[1])
+		if err != nil || inf {
+			continue
+		}
+		return hx, hy
+	}
+}
+
+// Compute Pedersen commitment value*P + blinding*H, where P is the
+// basic point and H is the independent generator with unknown discrete
+// logarithm. Both scalars are taken modulo Q. Commitment is additively
+// homomorphic: sum of two commitments is the commitment to the sum of
+// values with the sum of blindings. blinding must be random and secret
+// for the commitment to hide the value.
+func (c *Curve) Commit(value, blinding *big.Int) (x, y *big.Int, err error) {
+	v := big.NewInt(0).Mod(value, c.Q)
+	b := big.NewInt(0).Mod(blinding, c.Q)
+	defer zeroize(v, b)
+	x, y, vInf, err := c.ScalarMult(v, c.X, c.Y)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.Curve.Commit: %w", err)
+	}
+	hx, hy := c.pedersenH()
+	bx, by, bInf, err := c.ScalarMult(b, hx, hy)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.Curve.Commit: %w", err)
+	}
+	if vInf {
+		x, y = big.NewInt(0), big.NewInt(0)
+	}
+	if bInf {
+		bx, by = big.NewInt(0), big.NewInt(0)
+	}
+	if c.addInf(x, y, vInf, bx, by, bInf) {
+		return nil, nil, errors.New("gogost/gost3410.Curve.Commit: commitment is at infinity")
+	}
+	return x, y, nil
+}
+
+// Open the commitment: check that (x, y) is Commit(value, blinding).
+func (c *Curve) VerifyCommitment(x, y, value, blinding *big.Int) bool {
+	cx, cy, err := c.Commit(value, blinding)
+	if err != nil {
+		return false
+	}
+	return cx.Cmp(x) == 0 && cy.Cmp(y) == 0
+}
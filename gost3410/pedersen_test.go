@@ -0,0 +1,68 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"math/big"
+	"testing"
+	"testing/quick"
+)
+
+func TestPedersenH(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	hx, hy := c.pedersenH()
+	if !c.IsOnCurve(hx, hy) {
+		t.Fatal("H is not on the curve")
+	}
+	if _, _, inf, err := c.ScalarMult(c.Q, hx, hy); err != nil || !inf {
+		t.Fatal("H is not of Q order")
+	}
+	if hx.Cmp(c.X) == 0 {
+		t.Fatal("H equals to the basic point")
+	}
+}
+
+func TestCommitHomomorphism(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	f := func(a, b, r1, r2 uint64) bool {
+		va, vb := big.NewInt(0).SetUint64(a), big.NewInt(0).SetUint64(b)
+		b1, b2 := big.NewInt(0).SetUint64(r1), big.NewInt(0).SetUint64(r2)
+		x1, y1, err := c.Commit(va, b1)
+		if err != nil {
+			return false
+		}
+		if !c.VerifyCommitment(x1, y1, va, b1) || c.VerifyCommitment(x1, y1, vb, b1) && a != b {
+			return false
+		}
+		x2, y2, err := c.Commit(vb, b2)
+		if err != nil {
+			return false
+		}
+		sum := big.NewInt(0).Add(va, vb)
+		sumBlinding := big.NewInt(0).Add(b1, b2)
+		x, y, err := c.Commit(sum, sumBlinding)
+		if err != nil {
+			return false
+		}
+		if c.addInf(x1, y1, false, x2, y2, false) {
+			return false
+		}
+		return x.Cmp(x1) == 0 && y.Cmp(y1) == 0
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 20}); err != nil {
+		t.Error(err)
+	}
+}
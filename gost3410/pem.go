@@ -0,0 +1,60 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Decode all GOST keys from concatenated PEM blocks, like a bundle
+// with the key and its certificates chain. "PRIVATE KEY" (PKCS #8)
+// and "PUBLIC KEY" (PKIX) blocks are decoded, other ones are skipped
+// silently. Blocks failing to decode are skipped too, but their errors
+// are joined into the returned err, so it may be non-nil even if some
+// keys are successfully decoded.
+func DecodeAllPEM(data []byte) (keys []*PrivateKey, pubs []*PublicKey, err error) {
+	var errs []error
+	var block *pem.Block
+	for n := 0; ; n++ {
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case "PRIVATE KEY":
+			prv, e := ParsePKCS8(block.Bytes)
+			if e != nil {
+				errs = append(errs, fmt.Errorf("gogost/gost3410.DecodeAllPEM: block %d: %w", n, e))
+				continue
+			}
+			keys = append(keys, prv)
+		case "PUBLIC KEY":
+			pub, e := ParsePKIXPublicKey(block.Bytes)
+			if e != nil {
+				errs = append(errs, fmt.Errorf("gogost/gost3410.DecodeAllPEM: block %d: %w", n, e))
+				continue
+			}
+			pubs = append(pubs, pub)
+		}
+	}
+	err = errors.Join(errs...)
+	return
+}
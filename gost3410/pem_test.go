@@ -0,0 +1,91 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+)
+
+func TestPKIXRoundTrip(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParsePKIXPublicKey(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(pub) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestDecodeAllPEM(t *testing.T) {
+	prv, err := GenPrivateKey(CurveIdtc26gost341012512paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvDER, err := MarshalPKCS8(prv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle []byte
+	bundle = append(bundle, []byte("leading garbage\n")...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: prvDER})...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("cert")})...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("bad")})...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})...)
+	keys, pubs, err := DecodeAllPEM(bundle)
+	if err == nil {
+		t.Fatal("malformed block is not reported")
+	}
+	if len(keys) != 1 || keys[0].Key.Cmp(prv.Key) != 0 {
+		t.FailNow()
+	}
+	if len(pubs) != 1 || !pubs[0].Equal(pub) {
+		t.FailNow()
+	}
+	if _, _, err = DecodeAllPEM(bundle[:0]); err != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,246 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/mgm"
+)
+
+// PBKDF2 iterations count used by MarshalEncryptedPKCS8.
+const PKCS8Iterations = 10000
+
+// Limits of PBKDF2 parameters taken from the encrypted data: too many
+// iterations would hang the decryption and too short salt defeats its
+// purpose.
+const (
+	PBKDF2MaxIterations = 1 << 20
+	PBKDF2MinSaltLen    = 16
+)
+
+func checkPBKDF2Params(salt []byte, iterations int) error {
+	if iterations <= 0 || iterations > PBKDF2MaxIterations {
+		return fmt.Errorf("invalid iterations count %d", iterations)
+	}
+	if len(salt) < PBKDF2MinSaltLen {
+		return fmt.Errorf("too short salt (%d<%d)", len(salt), PBKDF2MinSaltLen)
+	}
+	return nil
+}
+
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// Marshal private key into unencrypted PKCS #8 PrivateKeyInfo DER form,
+// as RFC 9215 describes. Curve must have an OID.
+func MarshalPKCS8(prv *PrivateKey) ([]byte, error) {
+	ai, err := curveAlgorithmIdentifier(prv.C)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalPKCS8: %w", err)
+	}
+	key, err := asn1.Marshal(prv.Raw())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalPKCS8: %w", err)
+	}
+	return asn1.Marshal(pkcs8{Algo: ai, PrivateKey: key})
+}
+
+// Parse unencrypted PKCS #8 PrivateKeyInfo DER form.
+func ParsePKCS8(der []byte) (*PrivateKey, error) {
+	var info pkcs8
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKCS8: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParsePKCS8: trailing data")
+	}
+	c, err := curveFromAlgorithmIdentifier(info.Algo)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKCS8: %w", err)
+	}
+	var raw []byte
+	if _, err = asn1.Unmarshal(info.PrivateKey, &raw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKCS8: %w", err)
+	}
+	return NewPrivateKey(c, raw)
+}
+
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// GostR3412-15-Encryption-Parameters, where ukm is used as MGM's nonce
+type encryptionParams struct {
+	UKM []byte
+}
+
+type encryptedPKCS8 struct {
+	Algo          pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+func pkcs8KEK(passphrase, salt []byte, iterations int) []byte {
+	return pbkdf2.Key(
+		passphrase, salt, iterations,
+		gost3412128.KeySize, gost34112012512.New,
+	)
+}
+
+// Marshal private key into PKCS #8 EncryptedPrivateKeyInfo DER form.
+// PBES2 is used with PBKDF2-HMAC-Streebog-512 key derivation and
+// Kuznechik-MGM authenticated encryption.
+func MarshalEncryptedPKCS8(prv *PrivateKey, passphrase []byte) ([]byte, error) {
+	pt, err := MarshalPKCS8(prv)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	nonce := make([]byte, gost3412128.BlockSize)
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	nonce[0] &= 0x7F
+	aead, err := mgm.NewMGM(
+		gost3412128.NewCipher(pkcs8KEK(passphrase, salt, PKCS8Iterations)),
+		gost3412128.BlockSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: PKCS8Iterations,
+		KeyLength:      gost3412128.KeySize,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidTc26HMACGost341112512},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	encParams, err := asn1.Marshal(encryptionParams{UKM: nonce})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBKDF2,
+			Parameters: asn1.RawValue{FullBytes: kdfParams},
+		},
+		EncryptionScheme: pkix.AlgorithmIdentifier{
+			Algorithm:  oidTc26CipherKuznyechikMGM,
+			Parameters: asn1.RawValue{FullBytes: encParams},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalEncryptedPKCS8: %w", err)
+	}
+	return asn1.Marshal(encryptedPKCS8{
+		Algo: pkix.AlgorithmIdentifier{
+			Algorithm:  oidPBES2,
+			Parameters: asn1.RawValue{FullBytes: params},
+		},
+		EncryptedData: aead.Seal(nil, nonce, pt, nil),
+	})
+}
+
+// Parse PKCS #8 EncryptedPrivateKeyInfo made by MarshalEncryptedPKCS8.
+// Wrong passphrase or corrupted data leads to an error wrapping
+// mgm.InvalidTag.
+func ParseEncryptedPKCS8(der, passphrase []byte) (*PrivateKey, error) {
+	var info encryptedPKCS8
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParseEncryptedPKCS8: trailing data")
+	}
+	if !info.Algo.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: unsupported algorithm %s", info.Algo.Algorithm)
+	}
+	var params pbes2Params
+	if _, err = asn1.Unmarshal(info.Algo.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: unsupported KDF %s", params.KeyDerivationFunc.Algorithm)
+	}
+	var kdfParams pbkdf2Params
+	if _, err = asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if !kdfParams.PRF.Algorithm.Equal(oidTc26HMACGost341112512) {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: unsupported PRF %s", kdfParams.PRF.Algorithm)
+	}
+	if err = checkPBKDF2Params(kdfParams.Salt, kdfParams.IterationCount); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != gost3412128.KeySize {
+		return nil, errors.New("gogost/gost3410.ParseEncryptedPKCS8: invalid key length")
+	}
+	if !params.EncryptionScheme.Algorithm.Equal(oidTc26CipherKuznyechikMGM) {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: unsupported cipher %s", params.EncryptionScheme.Algorithm)
+	}
+	var encParams encryptionParams
+	if _, err = asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &encParams); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if len(encParams.UKM) != gost3412128.BlockSize || encParams.UKM[0]&0x80 > 0 {
+		return nil, errors.New("gogost/gost3410.ParseEncryptedPKCS8: invalid nonce")
+	}
+	aead, err := mgm.NewMGM(
+		gost3412128.NewCipher(pkcs8KEK(passphrase, kdfParams.Salt, kdfParams.IterationCount)),
+		gost3412128.BlockSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: %w", err)
+	}
+	if len(info.EncryptedData) <= aead.Overhead() {
+		return nil, errors.New("gogost/gost3410.ParseEncryptedPKCS8: encrypted data is too short")
+	}
+	pt, err := aead.Open(nil, encParams.UKM, info.EncryptedData, nil)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParseEncryptedPKCS8: wrong passphrase: %w", err)
+	}
+	return ParsePKCS8(pt)
+}
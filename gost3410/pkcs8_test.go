@@ -0,0 +1,113 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/mgm"
+)
+
+func TestPKCS8RoundTrip(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		der, err := MarshalPKCS8(prv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParsePKCS8(der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.C.Equal(c) || got.Key.Cmp(prv.Key) != 0 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestEncryptedPKCS8(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := MarshalEncryptedPKCS8(prv, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseEncryptedPKCS8(der, []byte("passphrase"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.C.Equal(c) || got.Key.Cmp(prv.Key) != 0 {
+		t.FailNow()
+	}
+	_, err = ParseEncryptedPKCS8(der, []byte("wrong"))
+	if !errors.Is(err, mgm.InvalidTag) {
+		t.Fatal(err)
+	}
+	der[len(der)-1] ^= 1
+	_, err = ParseEncryptedPKCS8(der, []byte("passphrase"))
+	if !errors.Is(err, mgm.InvalidTag) {
+		t.Fatal(err)
+	}
+}
+
+func TestPKCS8NoOID(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	c, err := NewCurve(std.P, std.Q, std.A, std.B, std.X, std.Y, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = MarshalPKCS8(prv); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestPBKDF2ParamsLimits(t *testing.T) {
+	salt := make([]byte, PBKDF2MinSaltLen)
+	if err := checkPBKDF2Params(salt, PKCS8Iterations); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkPBKDF2Params(salt, PBKDF2MaxIterations); err != nil {
+		t.Fatal(err)
+	}
+	for _, iterations := range []int{-1, 0, PBKDF2MaxIterations + 1, 1<<31 - 1} {
+		if checkPBKDF2Params(salt, iterations) == nil {
+			t.Fatal("iterations accepted", iterations)
+		}
+	}
+	if checkPBKDF2Params(nil, PKCS8Iterations) == nil ||
+		checkPBKDF2Params(salt[1:], PKCS8Iterations) == nil {
+		t.Fatal("short salt accepted")
+	}
+}
@@ -13,6 +13,9 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
@@ -21,6 +24,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"sync/atomic"
 )
 
 type PrivateKey struct {
@@ -42,7 +46,28 @@ func NewPrivateKey(c *Curve, raw []byte) (*PrivateKey, error) {
 	if k.Cmp(zero) == 0 {
 		return nil, errors.New("gogost/gost3410: zero private key")
 	}
-	return &PrivateKey{c, k.Mod(k, c.Q)}, nil
+	return &PrivateKey{C: c, Key: k.Mod(k, c.Q)}, nil
+}
+
+// Strict variant of NewPrivateKey for keys import: the curve is always
+// given explicitly, "raw" must be exactly c.PointSize() length and
+// little-endian scalar must be in [1, Q), without reducing modulo Q.
+func NewPrivateKeyRaw(c *Curve, raw []byte) (*PrivateKey, error) {
+	if c == nil {
+		return nil, errors.New("gogost/gost3410.NewPrivateKeyRaw: no curve")
+	}
+	pointSize := c.PointSize()
+	if len(raw) != pointSize {
+		return nil, fmt.Errorf("gogost/gost3410.NewPrivateKeyRaw: len(key)=%d != %d", len(raw), pointSize)
+	}
+	key := make([]byte, pointSize)
+	copy(key, raw)
+	reverse(key)
+	k := bytes2big(key)
+	if k.Sign() == 0 || k.Cmp(c.Q) >= 0 {
+		return nil, errors.New("gogost/gost3410.NewPrivateKeyRaw: key is out of [1, Q) range")
+	}
+	return &PrivateKey{C: c, Key: k}, nil
 }
 
 func GenPrivateKey(c *Curve, rand io.Reader) (*PrivateKey, error) {
@@ -53,6 +78,42 @@ func GenPrivateKey(c *Curve, rand io.Reader) (*PrivateKey, error) {
 	return NewPrivateKey(c, raw)
 }
 
+// Generate n private keys, reading the randomness from rand in bulk.
+// Each key is rejection sampled: candidate scalar, masked to Q's bit
+// length, is accepted only if it is within [1, Q) and differs from the
+// already generated keys, otherwise another one is read.
+func GenPrivateKeys(c *Curve, rand io.Reader, n int) ([]*PrivateKey, error) {
+	if n < 0 {
+		return nil, errors.New("gogost/gost3410.GenPrivateKeys: negative number of keys")
+	}
+	pointSize := c.PointSize()
+	mask := byte(0xFF >> uint(pointSize*8-c.Q.BitLen()))
+	buf := make([]byte, n*pointSize)
+	defer zeroizeBytes(buf)
+	prvs := make([]*PrivateKey, 0, n)
+	seen := make(map[string]struct{}, n)
+	for len(prvs) < n {
+		chunk := buf[:(n-len(prvs))*pointSize]
+		if _, err := io.ReadFull(rand, chunk); err != nil {
+			return nil, fmt.Errorf("gogost/gost3410.GenPrivateKeys: %w", err)
+		}
+		for ; len(chunk) > 0; chunk = chunk[pointSize:] {
+			raw := chunk[:pointSize]
+			raw[pointSize-1] &= mask
+			prv, err := NewPrivateKeyRaw(c, raw)
+			if err != nil {
+				continue
+			}
+			if _, ok := seen[string(raw)]; ok {
+				continue
+			}
+			seen[string(raw)] = struct{}{}
+			prvs = append(prvs, prv)
+		}
+	}
+	return prvs, nil
+}
+
 // Marshal little-endian private key. raw will be prv.C.PointSize() length.
 func (prv *PrivateKey) Raw() (raw []byte) {
 	raw = pad(prv.Key.Bytes(), prv.C.PointSize())
@@ -60,55 +121,288 @@ func (prv *PrivateKey) Raw() (raw []byte) {
 	return raw
 }
 
+func (prv *PrivateKey) CurveOf() *Curve {
+	return prv.C
+}
+
 func (prv *PrivateKey) PublicKey() (*PublicKey, error) {
-	x, y, err := prv.C.Exp(prv.Key, prv.C.X, prv.C.Y)
+	x, y, err := prv.C.ScalarBaseMult(prv.Key)
 	if err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.PublicKey: %w", err)
 	}
-	return &PublicKey{prv.C, x, y}, nil
+	return &PublicKey{C: prv.C, X: x, Y: y}, nil
+}
+
+// Is pub the public key of prv, for example when checking backup or
+// escrow record against the live key. Points are compared with
+// EqualConstantTime, keys on different curves never match.
+func (pub *PublicKey) MatchesPrivate(prv *PrivateKey) bool {
+	if prv == nil || !SameCurve(pub, prv) {
+		return false
+	}
+	derived, err := prv.PublicKey()
+	if err != nil {
+		return false
+	}
+	return pub.EqualConstantTime(derived)
 }
 
+// Sign the digest with random nonce k read from rand. As the standard
+// requires, if either r or s component is zero, then new nonce is read
+// and signing is repeated, so invalid signature is never produced.
+// Digest must be of the curve's DigestSize, otherwise ErrDigestSize
+// is returned.
 func (prv *PrivateKey) SignDigest(digest []byte, rand io.Reader) ([]byte, error) {
-	e := bytes2big(digest)
-	e.Mod(e, prv.C.Q)
-	if e.Cmp(zero) == 0 {
-		e = big.NewInt(1)
+	if err := prv.C.checkDigest(digest); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignDigest: %w", err)
 	}
+	e := prv.C.DigestToScalar(digest)
 	kRaw := make([]byte, prv.C.PointSize())
 	var err error
-	var k *big.Int
-	var r *big.Int
-	d := big.NewInt(0)
-	s := big.NewInt(0)
+	var r, s *big.Int
+	k := big.NewInt(0)
+	defer zeroizeBytes(kRaw)
+	defer zeroize(k)
 Retry:
 	if _, err = io.ReadFull(rand, kRaw); err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignDigest: %w", err)
 	}
-	k = bytes2big(kRaw)
+	k.SetBytes(kRaw)
 	k.Mod(k, prv.C.Q)
 	if k.Cmp(zero) == 0 {
 		goto Retry
 	}
-	r, _, err = prv.C.Exp(k, prv.C.X, prv.C.Y)
+	r, s, err = prv.signK(e, k)
 	if err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignDigest: %w", err)
 	}
-	r.Mod(r, prv.C.Q)
-	if r.Cmp(zero) == 0 {
+	if r == nil {
 		goto Retry
 	}
-	d.Mul(prv.Key, r)
-	k.Mul(k, e)
-	s.Add(d, k)
+	return rsToSignature(prv.C, r, s), nil
+}
+
+// Sign the digest once, like SignDigest does, returning the signature
+// both in native raw and MarshalSignatureDER encodings.
+func (prv *PrivateKey) SignMulti(rand io.Reader, digest []byte) (raw, der []byte, err error) {
+	raw, err = prv.SignDigest(digest, rand)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignMulti: %w", err)
+	}
+	der, err = MarshalSignatureDER(prv.C, raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignMulti: %w", err)
+	}
+	return raw, der, nil
+}
+
+// Sign the digest, like SignDigest does, and verify the signature with
+// the public key derived from prv, as a countermeasure against the fault
+// attacks: faulty signature may leak the private key, so an error is
+// returned instead of it. Deriving the public key and the verification
+// make it about four times slower than SignDigest.
+func (prv *PrivateKey) SignVerified(rand io.Reader, digest []byte) ([]byte, error) {
+	sig, err := prv.SignDigest(digest, rand)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignVerified: %w", err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignVerified: %w", err)
+	}
+	valid, err := pub.VerifyDigest(digest, sig)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignVerified: %w", err)
+	}
+	if !valid {
+		zeroizeBytes(sig)
+		return nil, errors.New("gogost/gost3410.PrivateKey.SignVerified: produced signature is invalid, possible fault")
+	}
+	return sig, nil
+}
+
+// Compute r and s signature components for e digest and k nonce.
+// nil r is returned if either of components is zero, so another nonce
+// has to be used.
+func (prv *PrivateKey) signK(e, k *big.Int) (r, s *big.Int, err error) {
+	r, s, _, _, err = prv.signKPoint(e, k)
+	return
+}
+
+// Same as signK, also returning k*P nonce commitment point.
+func (prv *PrivateKey) signKPoint(e, k *big.Int) (r, s, rx, ry *big.Int, err error) {
+	rx, ry, err = prv.C.ScalarBaseMult(k)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	r = big.NewInt(0).Mod(rx, prv.C.Q)
+	if r.Cmp(zero) == 0 {
+		return nil, nil, nil, nil, nil
+	}
+	d := big.NewInt(0).Mul(prv.Key, r)
+	ke := big.NewInt(0).Mul(k, e)
+	defer zeroize(d, ke)
+	s = big.NewInt(0).Add(d, ke)
 	s.Mod(s, prv.C.Q)
 	if s.Cmp(zero) == 0 {
+		return nil, nil, nil, nil, nil
+	}
+	return r, s, rx, ry, nil
+}
+
+// Sign the digest, like SignDigest does, also returning the proof: the
+// R = k*P nonce commitment point and the e digest scalar. It allows an
+// auditor to check the signing process with VerifyProof, without the
+// private key. k itself is not revealed.
+func (prv *PrivateKey) SignWithProof(rand io.Reader, digest []byte) (sig []byte, proof SignProof, err error) {
+	if err = prv.C.checkDigest(digest); err != nil {
+		return nil, proof, fmt.Errorf("gogost/gost3410.PrivateKey.SignWithProof: %w", err)
+	}
+	e := prv.C.DigestToScalar(digest)
+	kRaw := make([]byte, prv.C.PointSize())
+	var r, s, rx, ry *big.Int
+	k := big.NewInt(0)
+	defer zeroizeBytes(kRaw)
+	defer zeroize(k)
+Retry:
+	if _, err = io.ReadFull(rand, kRaw); err != nil {
+		return nil, proof, fmt.Errorf("gogost/gost3410.PrivateKey.SignWithProof: %w", err)
+	}
+	k.SetBytes(kRaw)
+	k.Mod(k, prv.C.Q)
+	if k.Cmp(zero) == 0 {
 		goto Retry
 	}
-	pointSize := prv.C.PointSize()
-	return append(
-		pad(s.Bytes(), pointSize),
-		pad(r.Bytes(), pointSize)...,
-	), nil
+	r, s, rx, ry, err = prv.signKPoint(e, k)
+	if err != nil {
+		return nil, proof, fmt.Errorf("gogost/gost3410.PrivateKey.SignWithProof: %w", err)
+	}
+	if r == nil {
+		goto Retry
+	}
+	return rsToSignature(prv.C, r, s), SignProof{Rx: rx, Ry: ry, E: e}, nil
+}
+
+// Sign the digest, like SignDigest does, also returning the recovery
+// id, that allows RecoverPublicKey to find the single signer's public
+// key from the signature and the digest.
+func (prv *PrivateKey) SignRecoverable(rand io.Reader, digest []byte) (sig []byte, recID byte, err error) {
+	sig, proof, err := prv.SignWithProof(rand, digest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("gogost/gost3410.PrivateKey.SignRecoverable: %w", err)
+	}
+	return sig, recoveryID(prv.C, proof.Rx, proof.Ry), nil
+}
+
+// Sign the digest with caller supplied nonce k, that must be within
+// [1, Q). It is intended for reproducing test vectors and for
+// threshold protocols only: any k reuse or predictability reveals the
+// private key (see RecoverKeyFromReusedNonce). Use SignDigest instead.
+func (prv *PrivateKey) SignWithK(digest []byte, k *big.Int) ([]byte, error) {
+	if err := prv.C.checkDigest(digest); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignWithK: %w", err)
+	}
+	if k.Sign() <= 0 || k.Cmp(prv.C.Q) >= 0 {
+		return nil, errors.New("gogost/gost3410.PrivateKey.SignWithK: k is out of range")
+	}
+	r, s, err := prv.signK(prv.C.DigestToScalar(digest), k)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignWithK: %w", err)
+	}
+	if r == nil {
+		return nil, errors.New("gogost/gost3410.PrivateKey.SignWithK: k leads to zero r or s")
+	}
+	return rsToSignature(prv.C, r, s), nil
+}
+
+// Sign the digest, like SignDigest does, with blinding side-channel
+// countermeasures. Basic point is multiplied by k' = k + m1*Q and
+// private key is replaced with d' = d + m2*Q (d itself can be already
+// blinded, see PrivateKeyBlinded), where m1 and m2 are random
+// 64-bit values: that changes the scalars bit patterns without changing
+// results modulo Q. GOST signing has no nonce inversion, so the
+// s = r*d + k*e linear combination itself is masked: it is computed as
+// b^-1 * (b*r*d' + b*k'*e) with random b, b^-1 being computed in
+// constant time. Signature is the same as SignDigest produces for the
+// same k.
+func (prv *PrivateKey) SignBlinded(rand io.Reader, digest []byte) ([]byte, error) {
+	return prv.signBlinded(rand, digest, nil)
+}
+
+// Sign the digest like SignBlinded does, using base + m2*Q instead of
+// d + m2*Q, where base is d + mBase*Q, if mBase is not nil.
+func (prv *PrivateKey) signBlinded(rand io.Reader, digest []byte, mBase *big.Int) ([]byte, error) {
+	c := prv.C
+	if err := c.checkDigest(digest); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	e := c.DigestToScalar(digest)
+	kRaw := make([]byte, c.PointSize())
+	mRaw := make([]byte, 8)
+	var err error
+	var r *big.Int
+	var inf bool
+	k := big.NewInt(0)
+	m := big.NewInt(0)
+	d := big.NewInt(0)
+	s := big.NewInt(0)
+	b := big.NewInt(0)
+	defer zeroizeBytes(kRaw)
+	defer zeroizeBytes(mRaw)
+	defer zeroize(k, m, d, b)
+Retry:
+	if _, err = io.ReadFull(rand, kRaw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	k.SetBytes(kRaw)
+	k.Mod(k, c.Q)
+	if k.Sign() == 0 {
+		goto Retry
+	}
+	if _, err = io.ReadFull(rand, mRaw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	m.SetBytes(mRaw)
+	k.Add(k, m.Mul(m, c.Q))
+	r, _, inf, err = c.ScalarMult(k, c.X, c.Y)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	if inf {
+		goto Retry
+	}
+	r.Mod(r, c.Q)
+	if r.Sign() == 0 {
+		goto Retry
+	}
+	if _, err = io.ReadFull(rand, mRaw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	m.SetBytes(mRaw)
+	if mBase != nil {
+		m.Add(m, mBase)
+	}
+	d.Add(prv.Key, m.Mul(m, c.Q))
+	if _, err = io.ReadFull(rand, kRaw); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignBlinded: %w", err)
+	}
+	b.SetBytes(kRaw)
+	b.Mod(b, c.Q)
+	if b.Sign() == 0 {
+		goto Retry
+	}
+	d.Mul(d, b)
+	d.Mul(d, r)
+	s.Mul(k, b)
+	s.Mul(s, e)
+	s.Add(s, d)
+	s.Mod(s, c.Q)
+	s.Mul(s, b.Set(c.modInverseQCT(b)))
+	s.Mod(s, c.Q)
+	if s.Sign() == 0 {
+		goto Retry
+	}
+	return rsToSignature(c, r, s), nil
 }
 
 // Sign the digest. opts argument is unused.
@@ -158,3 +452,57 @@ func (prv *PrivateKeyReverseDigestAndSignature) Sign(rand io.Reader, digest []by
 	reverse(sign)
 	return sign, err
 }
+
+// Private key with the persistent blinding factor m, that makes
+// SignBlinded use d + m*Q representation of the private key as a base
+// for its per-signature blinding. Only m is kept: d + m*Q is computed
+// from Prv.Key on each signing. Zero m is used until RefreshBlinding is
+// called. It is safe for concurrent use. m is held here deliberately,
+// not in PrivateKey: PrivateKey stays the plain curve and key pair,
+// that is freely copied and created with composite literals, and has no
+// mutable state shared between the copies. Use PrivateKey.RefreshBlinding
+// to create it.
+type PrivateKeyBlinded struct {
+	Prv *PrivateKey
+	m   atomic.Value // *big.Int
+}
+
+func (prv *PrivateKeyBlinded) Public() crypto.PublicKey {
+	return prv.Prv.Public()
+}
+
+// Sign the digest with SignBlinded. opts argument is unused.
+func (prv *PrivateKeyBlinded) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return prv.SignBlinded(rand, digest)
+}
+
+// Sign the digest, like PrivateKey.SignBlinded does, with d + m*Q as a
+// base of private key's blinding.
+func (prv *PrivateKeyBlinded) SignBlinded(rand io.Reader, digest []byte) ([]byte, error) {
+	m, _ := prv.m.Load().(*big.Int)
+	return prv.Prv.signBlinded(rand, digest, m)
+}
+
+// Start using the persistent blinding factor: return the key wrapped
+// into PrivateKeyBlinded with the new random m. prv itself is left
+// intact, as it holds no blinding state, so further refreshes have to
+// be done with the returned key's RefreshBlinding.
+func (prv *PrivateKey) RefreshBlinding(rand io.Reader) (*PrivateKeyBlinded, error) {
+	blinded := &PrivateKeyBlinded{Prv: prv}
+	if err := blinded.RefreshBlinding(rand); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.RefreshBlinding: %w", err)
+	}
+	return blinded, nil
+}
+
+// Replace the persistent blinding factor m with the new random 64-bit
+// one. Logical key, public key and signatures validity are unchanged.
+func (prv *PrivateKeyBlinded) RefreshBlinding(rand io.Reader) error {
+	mRaw := make([]byte, 8)
+	defer zeroizeBytes(mRaw)
+	if _, err := io.ReadFull(rand, mRaw); err != nil {
+		return fmt.Errorf("gogost/gost3410.PrivateKeyBlinded.RefreshBlinding: %w", err)
+	}
+	prv.m.Store(big.NewInt(0).SetBytes(mRaw))
+	return nil
+}
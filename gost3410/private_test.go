@@ -13,11 +13,18 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
 	"testing"
 )
 
@@ -30,3 +37,358 @@ func TestSignerInterface(t *testing.T) {
 	}
 	var _ crypto.Signer = prv
 }
+
+func TestDeterministicReader(t *testing.T) {
+	buf1 := make([]byte, 100)
+	buf2 := make([]byte, 100)
+	io.ReadFull(DeterministicReader([]byte("seed")), buf1)
+	r := DeterministicReader([]byte("seed"))
+	io.ReadFull(r, buf2[:7])
+	io.ReadFull(r, buf2[7:])
+	if !bytes.Equal(buf1, buf2) {
+		t.FailNow()
+	}
+	io.ReadFull(DeterministicReader([]byte("another")), buf2)
+	if bytes.Equal(buf1, buf2) {
+		t.FailNow()
+	}
+}
+
+func TestSignBlinded(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.PointSize())
+		rand.Read(digest)
+		sign, err := prv.SignBlinded(rand.Reader, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		valid, err := pub.VerifyDigest(digest, sign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.FailNow()
+		}
+		// The same nonce leads to the same signature
+		seed := []byte("blinding")
+		expected, err := prv.SignDigest(digest, DeterministicReader(seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sign, err = prv.SignBlinded(DeterministicReader(seed), digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(sign, expected) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestRefreshBlinding(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, c.PointSize())
+	rand.Read(digest)
+	seed := []byte("refresh")
+	before, err := prv.SignBlinded(DeterministicReader(seed), digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prv.RefreshBlinding(bytes.NewReader(nil)); err == nil {
+		t.FailNow()
+	}
+	blinded, err := prv.RefreshBlinding(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if blinded.Prv != prv {
+		t.FailNow()
+	}
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			if err = blinded.RefreshBlinding(rand.Reader); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if blinded.m.Load().(*big.Int).Sign() == 0 {
+			t.FailNow()
+		}
+		if !blinded.Public().(*PublicKey).Equal(pub) {
+			t.FailNow()
+		}
+		after, err := blinded.SignBlinded(DeterministicReader(seed), digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(after, before) {
+			t.FailNow()
+		}
+		valid, err := pub.VerifyDigest(digest, after)
+		if err != nil || !valid {
+			t.FailNow()
+		}
+	}
+	if err = blinded.RefreshBlinding(bytes.NewReader(nil)); err == nil {
+		t.FailNow()
+	}
+	// Changed key is used at once
+	prv.Key.Add(prv.Key, bigInt1)
+	pub, err = prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign, err := blinded.SignBlinded(rand.Reader, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := pub.VerifyDigest(digest, sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+}
+
+// Remembers all buffers filled with randomness
+type recordingReader struct {
+	bufs [][]byte
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	r.bufs = append(r.bufs, p)
+	return rand.Read(p)
+}
+
+func TestSignZeroizesNonce(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	rnd := &recordingReader{}
+	if _, err = prv.SignDigest(digest, rnd); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prv.SignBlinded(rnd, digest); err != nil {
+		t.Fatal(err)
+	}
+	if len(rnd.bufs) == 0 {
+		t.FailNow()
+	}
+	for _, buf := range rnd.bufs {
+		if !bytes.Equal(buf, make([]byte, len(buf))) {
+			t.Fatal("nonce material is not zeroized")
+		}
+	}
+}
+
+func TestZeroize(t *testing.T) {
+	v := big.NewInt(0).SetBytes(bytes.Repeat([]byte{0xFF}, 64))
+	words := v.Bits()
+	v.Rsh(v, 256)
+	zeroize(v)
+	if v.Sign() != 0 {
+		t.FailNow()
+	}
+	for _, w := range words[:cap(words)] {
+		if w != 0 {
+			t.FailNow()
+		}
+	}
+}
+
+func TestNewPrivateKeyRaw(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := NewPrivateKeyRaw(c, prv.Raw())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Key.Cmp(prv.Key) != 0 || got.C != c {
+			t.FailNow()
+		}
+		for _, l := range []int{0, c.PointSize() - 1, c.PointSize() + 1, 96} {
+			if _, err = NewPrivateKeyRaw(c, make([]byte, l)); err == nil {
+				t.Fatal("wrong length accepted", l)
+			}
+		}
+		for _, k := range []*big.Int{
+			big.NewInt(0),
+			c.Q,
+			big.NewInt(0).Add(c.Q, bigInt1),
+		} {
+			raw := pad(k.Bytes(), c.PointSize())
+			reverse(raw)
+			if _, err = NewPrivateKeyRaw(c, raw); err == nil {
+				t.Fatal("out of range key accepted", k)
+			}
+		}
+		raw := pad(big.NewInt(0).Sub(c.Q, bigInt1).Bytes(), c.PointSize())
+		reverse(raw)
+		if _, err = NewPrivateKeyRaw(c, raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := NewPrivateKeyRaw(nil, make([]byte, 32)); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestDigestSizeMismatch(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.DigestSize())
+		rand.Read(digest)
+		sig, err := prv.SignDigest(digest, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Streebog-512 digest for 256-bit curve and vice versa
+		other := make([]byte, 96-c.DigestSize())
+		if _, err = prv.SignDigest(other, rand.Reader); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if _, err = pub.VerifyDigest(other, sig); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if _, _, err = prv.SignWithProof(rand.Reader, other); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if _, _, err = prv.SignRecoverable(rand.Reader, other); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if _, err = prv.SignWithK(other, bigInt1); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if _, err = prv.SignBlinded(rand.Reader, other); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		blinded, err := prv.RefreshBlinding(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = blinded.SignBlinded(rand.Reader, other); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		_, proof, err := prv.SignWithProof(rand.Reader, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = VerifyProof(pub, other, sig, proof); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		if valid, err := pub.VerifyDigest(digest, sig); err != nil || !valid {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignVerified(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetB(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.DigestSize())
+		rand.Read(digest)
+		sig, err := prv.SignVerified(rand.Reader, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, err := pub.VerifyDigest(digest, sig); err != nil || !valid {
+			t.FailNow()
+		}
+		if _, err = prv.SignVerified(rand.Reader, digest[1:]); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestGenPrivateKeys(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetB(),
+	} {
+		prvs, err := GenPrivateKeys(c, rand.Reader, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(prvs) != 16 {
+			t.Fatal("wrong number of keys", len(prvs))
+		}
+		for i, prv := range prvs {
+			if prv.Key.Sign() <= 0 || prv.Key.Cmp(c.Q) >= 0 {
+				t.Fatal("key is out of range", i)
+			}
+			for _, other := range prvs[:i] {
+				if other.Key.Cmp(prv.Key) == 0 {
+					t.Fatal("keys are not distinct", i)
+				}
+			}
+		}
+	}
+	if _, err := GenPrivateKeys(CurveIdtc26gost341012256paramSetA(), bytes.NewReader(nil), 1); err == nil {
+		t.Fatal("empty rand accepted")
+	}
+}
+
+func TestGenPrivateKeysRejection(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	// Zero, valid key, its duplicate and another valid key
+	raw := make([]byte, 3*c.PointSize())
+	raw[c.PointSize()] = 1
+	raw[2*c.PointSize()] = 1
+	prvs, err := GenPrivateKeys(c, io.MultiReader(
+		bytes.NewReader(raw),
+		bytes.NewReader([]byte{2}),
+		bytes.NewReader(make([]byte, c.PointSize()-1)),
+	), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prvs[0].Key.Cmp(big.NewInt(1)) != 0 || prvs[1].Key.Cmp(big.NewInt(2)) != 0 {
+		t.Fatal(prvs[0].Key, prvs[1].Key)
+	}
+}
@@ -0,0 +1,75 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Intermediate values of the signing process: R = k*P nonce commitment
+// point and e digest scalar.
+type SignProof struct {
+	Rx *big.Int
+	Ry *big.Int
+	E  *big.Int
+}
+
+// Check that the proof is consistent with the digest and the signature
+// made by pub: e corresponds to the digest, r = Rx mod Q and
+// s*P = r*Pub + e*R.
+func VerifyProof(pub *PublicKey, digest, sig []byte, proof SignProof) (bool, error) {
+	c := pub.C
+	if proof.Rx == nil || proof.Ry == nil || proof.E == nil {
+		return false, errors.New("gogost/gost3410.VerifyProof: incomplete proof")
+	}
+	if err := c.checkDigest(digest); err != nil {
+		return false, fmt.Errorf("gogost/gost3410.VerifyProof: %w", err)
+	}
+	r, s, err := SignatureToRS(c, sig)
+	if err != nil {
+		return false, err
+	}
+	if proof.E.Cmp(c.DigestToScalar(digest)) != 0 {
+		return false, nil
+	}
+	if !c.IsOnCurve(proof.Rx, proof.Ry) {
+		return false, nil
+	}
+	if big.NewInt(0).Mod(proof.Rx, c.Q).Cmp(r) != 0 {
+		return false, nil
+	}
+	lx, ly, err := c.ScalarBaseMult(s)
+	if err != nil {
+		return false, err
+	}
+	rx, ry, rInf, err := c.ScalarMult(r, pub.X, pub.Y)
+	if err != nil {
+		return false, err
+	}
+	ex, ey, eInf, err := c.ScalarMult(proof.E, proof.Rx, proof.Ry)
+	if err != nil {
+		return false, err
+	}
+	if rInf {
+		rx, ry = big.NewInt(0), big.NewInt(0)
+	}
+	if c.addInf(rx, ry, rInf, ex, ey, eInf) {
+		return false, nil
+	}
+	return rx.Cmp(lx) == 0 && ry.Cmp(ly) == 0, nil
+}
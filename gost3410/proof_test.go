@@ -0,0 +1,74 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestSignWithProof(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.PointSize())
+		if _, err = rand.Read(digest); err != nil {
+			t.Fatal(err)
+		}
+		sign, proof, err := prv.SignWithProof(rand.Reader, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, err := pub.VerifyDigest(digest, sign); err != nil || !valid {
+			t.Fatal(c.Name, err)
+		}
+		if valid, err := VerifyProof(pub, digest, sign, proof); err != nil || !valid {
+			t.Fatal(c.Name, err)
+		}
+
+		digest[0] ^= 0x01
+		if valid, _ := VerifyProof(pub, digest, sign, proof); valid {
+			t.Fatal("accepted another digest")
+		}
+		digest[0] ^= 0x01
+		bad := proof
+		bad.E = big.NewInt(0).Add(proof.E, bigInt1)
+		if valid, _ := VerifyProof(pub, digest, sign, bad); valid {
+			t.Fatal("accepted another e")
+		}
+		bad = proof
+		bad.Ry = big.NewInt(0).Sub(c.P, proof.Ry)
+		if valid, _ := VerifyProof(pub, digest, sign, bad); valid {
+			t.Fatal("accepted negated R")
+		}
+		if _, err = VerifyProof(pub, digest, sign, SignProof{}); err == nil {
+			t.FailNow()
+		}
+	}
+}
@@ -17,6 +17,8 @@ package gost3410
 
 import (
 	"crypto"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"math/big"
 )
@@ -38,9 +40,9 @@ func NewPublicKey(c *Curve, raw []byte) (*PublicKey, error) {
 		key[i] = raw[len(raw)-i-1]
 	}
 	return &PublicKey{
-		c,
-		bytes2big(key[pointSize : 2*pointSize]),
-		bytes2big(key[:pointSize]),
+		C: c,
+		X: bytes2big(key[pointSize : 2*pointSize]),
+		Y: bytes2big(key[:pointSize]),
 	}, nil
 }
 
@@ -55,10 +57,47 @@ func (pub *PublicKey) Raw() (raw []byte) {
 	return raw
 }
 
+func (pub *PublicKey) CurveOf() *Curve {
+	return pub.C
+}
+
+// Verify the signature of the digest, that must be of the curve's
+// DigestSize, otherwise ErrDigestSize is returned.
 func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
+	ok, _, err := pub.VerifyDebug(digest, signature)
+	return ok, err
+}
+
+// Verify the signature, like VerifyDigest does, but with the digest
+// bytes taken in the opposite order. It is intended only for interop
+// with non-conformant peers, that sign the digest in the wrong
+// endianness: such signatures fail VerifyDigest.
+func (pub *PublicKey) VerifyDigestReversed(digest, signature []byte) (bool, error) {
+	reversed := append([]byte{}, digest...)
+	reverse(reversed)
+	return pub.VerifyDigest(reversed, signature)
+}
+
+// Verify the signature, like VerifyDigest does, also returning the
+// recomputed R value (modulo Q), that has to be equal to signature's r.
+// It is intended for diagnosing verification failures: recomputedR is
+// nil if signature's r or s are out of range.
+func (pub *PublicKey) VerifyDebug(digest, signature []byte) (ok bool, recomputedR *big.Int, err error) {
+	return pub.verify(nil, digest, signature)
+}
+
+// Verify the signature, using pub's wNAF precomputation table, if it
+// is not nil.
+func (pub *PublicKey) verify(table *wnafTable, digest, signature []byte) (ok bool, recomputedR *big.Int, err error) {
+	if pub.IsIdentity() {
+		return false, nil, errors.New("gogost/gost3410: public key is the identity")
+	}
 	pointSize := pub.C.PointSize()
 	if len(signature) != 2*pointSize {
-		return false, fmt.Errorf("gogost/gost3410: len(signature)=%d != %d", len(signature), 2*pointSize)
+		return false, nil, fmt.Errorf("gogost/gost3410: len(signature)=%d != %d", len(signature), 2*pointSize)
+	}
+	if err = pub.C.checkDigest(digest); err != nil {
+		return false, nil, err
 	}
 	s := bytes2big(signature[:pointSize])
 	r := bytes2big(signature[pointSize:])
@@ -66,13 +105,9 @@ func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
 		r.Cmp(pub.C.Q) >= 0 ||
 		s.Cmp(zero) <= 0 ||
 		s.Cmp(pub.C.Q) >= 0 {
-		return false, nil
-	}
-	e := bytes2big(digest)
-	e.Mod(e, pub.C.Q)
-	if e.Cmp(zero) == 0 {
-		e = big.NewInt(1)
+		return false, nil, nil
 	}
+	e := pub.C.DigestToScalar(digest)
 	v := big.NewInt(0)
 	v.ModInverse(e, pub.C.Q)
 	z1 := big.NewInt(0)
@@ -82,13 +117,22 @@ func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
 	z2.Mul(r, v)
 	z2.Mod(z2, pub.C.Q)
 	z2.Sub(pub.C.Q, z2)
-	p1x, p1y, err := pub.C.Exp(z1, pub.C.X, pub.C.Y)
+	p1x, p1y, err := pub.C.ScalarBaseMult(z1)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
-	q1x, q1y, err := pub.C.Exp(z2, pub.X, pub.Y)
-	if err != nil {
-		return false, err
+	var q1x, q1y *big.Int
+	if table != nil {
+		var isInfinity bool
+		q1x, q1y, isInfinity = table.mult(pub.C, z2)
+		if isInfinity {
+			return false, nil, errors.New("gogost/gost3410: result is at infinity")
+		}
+	} else {
+		q1x, q1y, err = pub.C.Exp(z2, pub.X, pub.Y)
+		if err != nil {
+			return false, nil, err
+		}
 	}
 	lm := big.NewInt(0)
 	lm.Sub(q1x, p1x)
@@ -108,13 +152,47 @@ func (pub *PublicKey) VerifyDigest(digest, signature []byte) (bool, error) {
 		lm.Add(lm, pub.C.P)
 	}
 	lm.Mod(lm, pub.C.Q)
-	return lm.Cmp(r) == 0, nil
+	return lm.Cmp(r) == 0, lm, nil
 }
 
+// Are both keys the same point on the curves with the same parameters.
+// Curves are compared by their parameters, not by pointers or names.
 func (our *PublicKey) Equal(theirKey crypto.PublicKey) bool {
 	their, ok := theirKey.(*PublicKey)
 	if !ok {
 		return false
 	}
+	if our.IsIdentity() || their.IsIdentity() {
+		return our.IsIdentity() && their.IsIdentity() && our.C.Equal(their.C)
+	}
 	return our.X.Cmp(their.X) == 0 && our.Y.Cmp(their.Y) == 0 && our.C.Equal(their.C)
 }
+
+// Same as Equal, but coordinates are compared in constant time over
+// their fixed-width encodings, not revealing where they first differ.
+// Useful for key pinning. Curve parameters are public and compared
+// as usual.
+func (our *PublicKey) EqualConstantTime(their *PublicKey) bool {
+	if their == nil || !our.C.Equal(their.C) {
+		return false
+	}
+	return subtle.ConstantTimeCompare(our.rawOrZero(), their.rawOrZero()) == 1
+}
+
+// Raw encoding with the point at infinity given as all zeros.
+func (pub *PublicKey) rawOrZero() []byte {
+	if pub.X == nil || pub.Y == nil {
+		return make([]byte, 2*pub.C.PointSize())
+	}
+	return pub.Raw()
+}
+
+// Is the key the point at infinity. It is represented either with nil
+// coordinates (as ScalarMult returns) or with zero ones (as all-zero
+// raw encoding gives, that is never on a curve with non-zero B).
+func (pub *PublicKey) IsIdentity() bool {
+	if pub.X == nil || pub.Y == nil {
+		return pub.X == nil && pub.Y == nil
+	}
+	return pub.X.Sign() == 0 && pub.Y.Sign() == 0
+}
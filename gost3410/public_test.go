@@ -0,0 +1,368 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestPublicKeyEqualDistinctCurves(t *testing.T) {
+	c1 := CurveIdtc26gost341012256paramSetB()
+	c2 := CurveIdGostR34102001CryptoProAParamSet()
+	if c1 == c2 || c1.Name == c2.Name {
+		t.FailNow()
+	}
+	prv, err := GenPrivateKey(c1, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub1, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, err := NewPublicKey(c2, pub1.Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub1.Equal(pub2) || !pub2.Equal(pub1) {
+		t.FailNow()
+	}
+	pub3, err := NewPublicKey(CurveIdtc26gost341012256paramSetC(), pub1.Raw())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub1.Equal(pub3) {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeyEqualEdwardsMismatch(t *testing.T) {
+	c1 := CurveIdtc26gost341012256paramSetA()
+	c2, err := NewCurve(c1.P, c1.Q, c1.A, c1.B, c1.X, c1.Y, nil, nil, c1.Co)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c1.Equal(c2) || c2.Equal(c1) {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeyIsIdentity(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	pub, err := NewPublicKey(c, make([]byte, 2*c.PointSize()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.IsIdentity() {
+		t.FailNow()
+	}
+	x, y, inf, err := c.ScalarMult(c.Q, c.X, c.Y)
+	if err != nil || !inf {
+		t.FailNow()
+	}
+	pubInf := &PublicKey{C: c, X: x, Y: y}
+	if !pubInf.IsIdentity() || !pubInf.Equal(pub) {
+		t.FailNow()
+	}
+	pubBase := &PublicKey{C: c, X: c.X, Y: c.Y}
+	if pubBase.IsIdentity() || pubBase.Equal(pub) || pub.Equal(pubBase) {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeyIdentityVerify(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pub := range []*PublicKey{
+		{C: c},
+		{C: c, X: big.NewInt(0), Y: big.NewInt(0)},
+	} {
+		if _, err = pub.VerifyDigest(digest, sign); err == nil {
+			t.Fatal("identity key is accepted")
+		}
+		if _, err = pub.Precompute().VerifyDigest(digest, sign); err == nil {
+			t.Fatal("precomputed identity key is accepted")
+		}
+		for _, ok := range pub.VerifyMany([][]byte{digest, digest}, [][]byte{sign, sign}) {
+			if ok {
+				t.Fatal("identity key is accepted in batch")
+			}
+		}
+	}
+}
+
+func TestVerifyDebug(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := prv.PublicKey()
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, _, _ := SignatureToRS(c, sign)
+	ok, recomputedR, err := pub.VerifyDebug(digest, sign)
+	if err != nil || !ok || recomputedR.Cmp(r) != 0 {
+		t.FailNow()
+	}
+	digest[0] ^= 1
+	ok, recomputedR, err = pub.VerifyDebug(digest, sign)
+	if err != nil || ok || recomputedR == nil || recomputedR.Cmp(r) == 0 {
+		t.FailNow()
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fpr := pub.Fingerprint()
+	if len(fpr) != 32 {
+		t.FailNow()
+	}
+	pointSize := c.PointSize()
+	be := append(pad(pub.X.Bytes(), pointSize), pad(pub.Y.Bytes(), pointSize)...)
+	le := append([]byte{}, be...)
+	reverse(le[:pointSize])
+	reverse(le[pointSize:])
+	pubLE, err := NewPublicKey(c, le)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubBE := &PublicKey{
+		C: c,
+		X: bytes2big(be[:pointSize]),
+		Y: bytes2big(be[pointSize:]),
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubSPKI, err := ParsePKIXPublicKey(spki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, other := range []*PublicKey{pubLE, pubBE, pubSPKI} {
+		if !bytes.Equal(other.Fingerprint(), fpr) {
+			t.FailNow()
+		}
+	}
+	s := pub.FingerprintString()
+	if len(s) != 32*3-1 || strings.Count(s, ":") != 31 {
+		t.Fatal(s)
+	}
+	if pub.Fingerprint() == nil || (&PublicKey{C: &Curve{}}).Fingerprint() != nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyWithSPKI(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyWithSPKI(spki, digest, sign); err != nil || !ok {
+		t.Fatal(err)
+	}
+	digest[0] ^= 0x01
+	if ok, err := VerifyWithSPKI(spki, digest, sign); err != nil || ok {
+		t.Fatal(err)
+	}
+	if _, err := VerifyWithSPKI(spki[:len(spki)-1], digest, sign); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeySmallCoordinate(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	x := big.NewInt(0)
+	y := big.NewInt(0)
+	for x.SetInt64(1); ; x.Add(x, bigInt1) {
+		rhs := big.NewInt(0).Mul(x, x)
+		rhs.Add(rhs, c.A)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, c.B)
+		rhs.Mod(rhs, c.P)
+		if y.ModSqrt(rhs, c.P) != nil {
+			break
+		}
+	}
+	pub := &PublicKey{C: c, X: x, Y: y}
+	if !c.IsOnCurve(x, y) {
+		t.FailNow()
+	}
+	raw := pub.Raw()
+	if len(raw) != 2*c.PointSize() {
+		t.Fatal("raw length", len(raw))
+	}
+	if !bytes.Equal(raw[1:c.PointSize()], make([]byte, c.PointSize()-1)) {
+		t.Fatal("X is not left-padded")
+	}
+	pubParsed, err := NewPublicKey(c, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pubParsed.X.Cmp(x) != 0 || pubParsed.Y.Cmp(y) != 0 {
+		t.FailNow()
+	}
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubParsed, err = ParsePKIXPublicKey(spki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pubParsed.X.Cmp(x) != 0 || pubParsed.Y.Cmp(y) != 0 {
+		t.FailNow()
+	}
+
+	sign := rsToSignature(c, bigInt1, big.NewInt(2))
+	if len(sign) != 2*c.PointSize() {
+		t.Fatal("signature length", len(sign))
+	}
+	r, s, err := SignatureToRS(c, sign)
+	if err != nil || r.Cmp(bigInt1) != 0 || s.Cmp(big.NewInt(2)) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestPublicKeyEqualConstantTime(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	keys := []*PublicKey{
+		{C: c, X: c.X, Y: c.Y},
+		{C: CurveIdGostR34102001CryptoProAParamSet(), X: c.X, Y: c.Y},
+		{C: CurveIdtc26gost341012256paramSetC(), X: c.X, Y: c.Y},
+		{C: c},
+		{C: c, X: big.NewInt(0), Y: big.NewInt(0)},
+	}
+	for i := 0; i < 3; i++ {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, pub)
+	}
+	for _, our := range keys {
+		for _, their := range keys {
+			if our.EqualConstantTime(their) != our.Equal(their) {
+				t.FailNow()
+			}
+		}
+		if our.EqualConstantTime(nil) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestVerifyDigestReversed(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, c.DigestSize())
+	if _, err = rand.Read(digest); err != nil {
+		t.Fatal(err)
+	}
+	digest[0] |= 1
+	reversed := append([]byte{}, digest...)
+	reverse(reversed)
+	sig, err := prv.SignDigest(reversed, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pub.VerifyDigestReversed(digest, sig); err != nil || !ok {
+		t.Fatal("reversed signature is not verified", err)
+	}
+	if ok, _ := pub.VerifyDigest(digest, sig); ok {
+		t.Fatal("reversed signature is verified conformantly")
+	}
+}
+
+func TestMatchesPrivate(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv1, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv2, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub1, err := prv1.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub1.MatchesPrivate(prv1) {
+		t.Fatal("matching pair is rejected")
+	}
+	if pub1.MatchesPrivate(prv2) || pub1.MatchesPrivate(nil) {
+		t.Fatal("non-matching pair is accepted")
+	}
+	other := &PrivateKey{C: CurveIdtc26gost341012256paramSetA(), Key: prv1.Key}
+	if pub1.MatchesPrivate(other) {
+		t.Fatal("key on different curve is accepted")
+	}
+}
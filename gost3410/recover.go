@@ -0,0 +1,87 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Get the recovery id of R = k*P nonce commitment point: R.x = r + j*Q
+// and recovery id is j*2 + (R.y & 1).
+func recoveryID(c *Curve, rx, ry *big.Int) byte {
+	j := big.NewInt(0).Quo(rx, c.Q)
+	return byte(j.Uint64()<<1) | byte(ry.Bit(0))
+}
+
+// Recover the public key from the digest's signature and its recovery
+// id, made by SignRecoverable. As s*P = e*R + r*pub, pub is computed as
+// r^-1 * (s*P - e*R), where R point is determined by recID. Recovered
+// key is checked to verify the signature.
+func RecoverPublicKey(c *Curve, digest, sig []byte, recID byte) (*PublicKey, error) {
+	if err := c.checkDigest(digest); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverPublicKey: %w", err)
+	}
+	r, s, err := SignatureToRS(c, sig)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverPublicKey: %w", err)
+	}
+	rx := big.NewInt(int64(recID >> 1))
+	rx.Mul(rx, c.Q)
+	rx.Add(rx, r)
+	p1, p2, ok := c.PointsWithX(rx)
+	if !ok {
+		return nil, errors.New("gogost/gost3410.RecoverPublicKey: no R point for recovery id")
+	}
+	ry := p1[1]
+	if ry.Bit(0) != uint(recID&1) {
+		ry = p2[1]
+	}
+	rInv := big.NewInt(0).ModInverse(r, c.Q)
+	u1 := big.NewInt(0).Mul(s, rInv)
+	u1.Mod(u1, c.Q)
+	u2 := c.DigestToScalar(digest)
+	u2.Mul(u2, rInv)
+	u2.Neg(u2)
+	u2.Mod(u2, c.Q)
+	x, y, inf1, err := c.ScalarMult(u1, c.X, c.Y)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverPublicKey: %w", err)
+	}
+	qx, qy, inf2, err := c.ScalarMult(u2, rx, ry)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverPublicKey: %w", err)
+	}
+	if inf1 {
+		x, y = big.NewInt(0), big.NewInt(0)
+	}
+	if inf2 {
+		qx, qy = big.NewInt(0), big.NewInt(0)
+	}
+	if c.addInf(x, y, inf1, qx, qy, inf2) {
+		return nil, errors.New("gogost/gost3410.RecoverPublicKey: recovered key is at infinity")
+	}
+	pub := &PublicKey{C: c, X: x, Y: y}
+	ok, err = pub.VerifyDigest(digest, sig)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.RecoverPublicKey: %w", err)
+	}
+	if !ok {
+		return nil, errors.New("gogost/gost3410.RecoverPublicKey: recovered key does not verify the signature")
+	}
+	return pub, nil
+}
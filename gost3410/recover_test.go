@@ -0,0 +1,62 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestSignRecoverable(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdGostR34102001TestParamSet(),
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetC(),
+	} {
+		for i := 0; i < 4; i++ {
+			prv, err := GenPrivateKey(c, rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pub, err := prv.PublicKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			digest := make([]byte, c.DigestSize())
+			if _, err = rand.Read(digest); err != nil {
+				t.Fatal(err)
+			}
+			sig, recID, err := prv.SignRecoverable(rand.Reader, digest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			recovered, err := RecoverPublicKey(c, digest, sig, recID)
+			if err != nil {
+				t.Fatal(c.Name, err)
+			}
+			if !recovered.Equal(pub) {
+				t.Fatal(c.Name, "wrong key is recovered")
+			}
+			if other, err := RecoverPublicKey(c, digest, sig, recID^1); err == nil && other.Equal(pub) {
+				t.Fatal(c.Name, "key is recovered with wrong recovery id")
+			}
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	tc26NamePrefix     = "id-tc26-gost-3410-12-"
+	tc26NamePrefixLong = "id-tc26-gost-3410-2012-"
+)
+
+var (
+	registryOnce sync.Once
+	registryMu   sync.RWMutex
+	registry     []*Curve
+
+	ErrNoCommonCurve = errors.New("gogost/gost3410: no mutually supported curve")
+)
+
+func registryInit() {
+	registry = []*Curve{
+		CurveGostR34102001ParamSetcc(),
+		CurveIdGostR34102001TestParamSet(),
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012256paramSetC(),
+		CurveIdtc26gost341012256paramSetD(),
+		CurveIdtc26gost341012512paramSetTest(),
+		CurveIdtc26gost341012512paramSetA(),
+		CurveIdtc26gost341012512paramSetB(),
+		CurveIdtc26gost341012512paramSetC(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdGostR34102001CryptoProBParamSet(),
+		CurveIdGostR34102001CryptoProCParamSet(),
+		CurveIdGostR34102001CryptoProXchAParamSet(),
+		CurveIdGostR34102001CryptoProXchBParamSet(),
+	}
+}
+
+// Register the curve, making it available through CurveByName,
+// CurveByOID and RegisteredCurves. Curve parameters are checked with
+// Validate. Curves with already registered name or OID are rejected.
+// Registered curve is shared by all its users, so it must not be
+// modified afterwards.
+func RegisterCurve(c *Curve) error {
+	if c.Name == "" || c.Name == "unknown" {
+		return errors.New("gogost/gost3410: curve has no name")
+	}
+	if err := c.Validate(); err != nil {
+		return fmt.Errorf("gogost/gost3410.RegisterCurve: %w", err)
+	}
+	registryOnce.Do(registryInit)
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, r := range registry {
+		if r.Name == c.Name {
+			return fmt.Errorf("gogost/gost3410: curve %s is already registered", c.Name)
+		}
+		if len(c.OID) > 0 && r.OID.Equal(c.OID) {
+			return fmt.Errorf("gogost/gost3410: curve with OID %s is already registered", c.OID)
+		}
+	}
+	registry = append(registry, c)
+	return nil
+}
+
+// Get all registered curves, including the built-in ones. Returned
+// curves are shared and must not be modified, as CurveByName and
+// CurveByOID results too.
+func RegisteredCurves() []*Curve {
+	registryOnce.Do(registryInit)
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return append([]*Curve{}, registry...)
+}
+
+// Find registered curve by its name. TC26 curves are also found by
+// their "id-tc26-gost-3410-2012-*" names, used in RFC 7836, besides
+// the "id-tc26-gost-3410-12-*" ones of the registry. nil is returned
+// if there is none.
+func CurveByName(name string) *Curve {
+	if strings.HasPrefix(name, tc26NamePrefixLong) {
+		name = tc26NamePrefix + strings.TrimPrefix(name, tc26NamePrefixLong)
+	}
+	for _, c := range RegisteredCurves() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Find registered curve by its OID. nil is returned if there is none.
+func CurveByOID(oid asn1.ObjectIdentifier) *Curve {
+	for _, c := range RegisteredCurves() {
+		if c.OID.Equal(oid) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Choose the first curve from our preference ordered OIDs, that is
+// also among theirs and is registered. ErrNoCommonCurve is returned if
+// there is none.
+func NegotiateCurve(ours, theirs []asn1.ObjectIdentifier) (*Curve, error) {
+	for _, oid := range ours {
+		for _, their := range theirs {
+			if !oid.Equal(their) {
+				continue
+			}
+			if c := CurveByOID(oid); c != nil {
+				return c, nil
+			}
+		}
+	}
+	return nil, ErrNoCommonCurve
+}
+
+// Get all registered curves with PointSize equal to rawLen/2: the
+// candidates for the raw LE(X)||LE(Y) public key of that length. Many
+// curves share the same size, so the caller has to disambiguate them
+// itself, for example by checking IsOnCurve.
+func GuessCurve(rawLen int) []*Curve {
+	if rawLen%2 != 0 {
+		return nil
+	}
+	var cs []*Curve
+	for _, c := range RegisteredCurves() {
+		if c.PointSize() == rawLen/2 {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
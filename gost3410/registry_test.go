@@ -0,0 +1,219 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// Remove the curve registered by the test.
+func unregisterCurve(c *Curve) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for i, r := range registry {
+		if r == c {
+			registry = append(registry[:i], registry[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestRegisteredCurvesAreValid(t *testing.T) {
+	for _, c := range RegisteredCurves() {
+		if err := c.Validate(); err != nil {
+			t.Fatal(c.Name, err)
+		}
+		if CurveByName(c.Name) == nil {
+			t.Fatal(c.Name, "not found by name")
+		}
+		if !CurveByOID(c.OID).Equal(c) {
+			t.Fatal(c.Name, "not found by OID")
+		}
+	}
+}
+
+func TestRegisterCustomCurve(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	c, err := NewCurve(std.P, std.Q, std.A, std.B, std.X, std.Y, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = RegisterCurve(c); err == nil {
+		t.Fatal("unknown name is accepted")
+	}
+	c.Name = "custom-test-curve"
+	c.OID = std.OID
+	if err = RegisterCurve(c); err == nil {
+		t.Fatal("duplicate OID is accepted")
+	}
+	c.OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+	if err = RegisterCurve(c); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterCurve(c) })
+	if CurveByName("custom-test-curve") != c {
+		t.FailNow()
+	}
+	if CurveByOID(c.OID) != c {
+		t.FailNow()
+	}
+	if err = RegisterCurve(c); err == nil {
+		t.Fatal("duplicate name is accepted")
+	}
+	var found bool
+	for _, r := range RegisteredCurves() {
+		if r == c {
+			found = true
+		}
+	}
+	if !found {
+		t.FailNow()
+	}
+}
+
+func TestValidateRejectsBadOrder(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	c, err := NewCurve(
+		std.P, big.NewInt(0).Add(std.Q, bigInt2),
+		std.A, std.B, std.X, std.Y, nil, nil, nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Name = "bad-order"
+	if RegisterCurve(c) == nil {
+		t.FailNow()
+	}
+}
+
+func TestBitSize(t *testing.T) {
+	for _, c := range RegisteredCurves() {
+		bits := c.BitSize()
+		if bits != 256 && bits != 512 {
+			t.Fatal(c.Name, bits)
+		}
+		if c.P.BitLen() > bits || c.P.BitLen() <= bits-8 {
+			t.Fatal(c.Name, c.P.BitLen())
+		}
+		if c.Is512() != (bits == 512) || c.Is512() != (c.PointSize() == 64) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestNegotiateCurve(t *testing.T) {
+	a := CurveIdtc26gost341012256paramSetA().OID
+	b := CurveIdtc26gost341012256paramSetB().OID
+	c512 := CurveIdtc26gost341012512paramSetC().OID
+	unknown := asn1.ObjectIdentifier{1, 2, 3, 4}
+	c, err := NegotiateCurve(
+		[]asn1.ObjectIdentifier{unknown, c512, a, b},
+		[]asn1.ObjectIdentifier{b, unknown, a},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.OID.Equal(a) {
+		t.Fatal(c.Name)
+	}
+	_, err = NegotiateCurve(
+		[]asn1.ObjectIdentifier{a, unknown},
+		[]asn1.ObjectIdentifier{b, c512, unknown},
+	)
+	if !errors.Is(err, ErrNoCommonCurve) {
+		t.Fatal(err)
+	}
+	if _, err = NegotiateCurve(nil, []asn1.ObjectIdentifier{a}); err != ErrNoCommonCurve {
+		t.Fatal(err)
+	}
+}
+
+func TestGuessCurve(t *testing.T) {
+	cs := GuessCurve(64)
+	if len(cs) == 0 {
+		t.FailNow()
+	}
+	var found bool
+	for _, c := range cs {
+		if c.PointSize() != 32 {
+			t.Fatal(c.Name)
+		}
+		if c.Equal(CurveIdtc26gost341012256paramSetB()) {
+			found = true
+		}
+	}
+	if !found {
+		t.FailNow()
+	}
+	var count int
+	for _, c := range RegisteredCurves() {
+		if c.PointSize() == 32 {
+			count++
+		}
+	}
+	if len(cs) != count {
+		t.FailNow()
+	}
+	for _, c := range GuessCurve(128) {
+		if c.PointSize() != 64 {
+			t.Fatal(c.Name)
+		}
+	}
+	if GuessCurve(63) != nil || GuessCurve(0) != nil {
+		t.FailNow()
+	}
+}
+
+func TestCurveWithName(t *testing.T) {
+	std := CurveIdtc26gost341012512paramSetB()
+	c, err := NewCurve(std.P, std.Q, std.A, std.B, std.X, std.Y, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oid := asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 2}
+	named := c.WithName("custom-named-curve", oid)
+	if named == c || c.Name != "unknown" || c.OID != nil {
+		t.Fatal("curve itself is changed")
+	}
+	if named.Name != "custom-named-curve" || !named.OID.Equal(oid) || !named.Equal(c) {
+		t.FailNow()
+	}
+	oid[len(oid)-1] = 3
+	if named.OID.Equal(oid) {
+		t.Fatal("OID is not copied")
+	}
+	if err = RegisterCurve(named); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { unregisterCurve(named) })
+	if CurveByName("custom-named-curve") != named {
+		t.FailNow()
+	}
+	spki, err := MarshalPKIXPublicKey(&PublicKey{C: named, X: named.X, Y: named.Y})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePKIXPublicKey(spki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.C != named || pub.X.Cmp(named.X) != 0 || pub.Y.Cmp(named.Y) != 0 {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,148 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type signatureDER struct {
+	R *big.Int
+	S *big.Int
+}
+
+// Encode native BE(s)||BE(r) signature as DER SEQUENCE { r INTEGER,
+// s INTEGER }, as ECDSA-like X.509 consumers expect. Each half of the
+// native signature is c.PointSize() long, r is (k*P).X mod Q and s is
+// r*d + k*e mod Q. Only the layout is converted: GOST signature still
+// can not be verified with ECDSA algorithm.
+func MarshalSignatureDER(c *Curve, sig []byte) ([]byte, error) {
+	r, s, err := SignatureToRS(c, sig)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalSignatureDER: %w", err)
+	}
+	return asn1.Marshal(signatureDER{R: r, S: s})
+}
+
+// Decode DER SEQUENCE { r INTEGER, s INTEGER } into native signature.
+// Parsing is strict: non-minimal length and integer encodings,
+// negative integers and trailing data are rejected, so there are no
+// multiple encodings of the same signature.
+func UnmarshalSignatureDER(c *Curve, der []byte) ([]byte, error) {
+	sig, err := unmarshalSignatureDER(c, der, true)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.UnmarshalSignatureDER: %w", err)
+	}
+	return sig, nil
+}
+
+// The same as UnmarshalSignatureDER, but tolerates non-minimal length
+// and integer encodings of sloppy producers. Negative integers and
+// trailing data are still rejected.
+func UnmarshalSignatureDERLax(c *Curve, der []byte) ([]byte, error) {
+	sig, err := unmarshalSignatureDER(c, der, false)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.UnmarshalSignatureDERLax: %w", err)
+	}
+	return sig, nil
+}
+
+// Alias of MarshalSignatureDER for ECDSA-oriented tooling.
+func MarshalSignatureECDSAStyle(c *Curve, sig []byte) ([]byte, error) {
+	return MarshalSignatureDER(c, sig)
+}
+
+// Alias of UnmarshalSignatureDER, the inverse of
+// MarshalSignatureECDSAStyle.
+func UnmarshalSignatureECDSAStyle(c *Curve, der []byte) ([]byte, error) {
+	return UnmarshalSignatureDER(c, der)
+}
+
+func unmarshalSignatureDER(c *Curve, der []byte, strict bool) ([]byte, error) {
+	seq, rest, err := derTLV(der, 0x30, strict)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data")
+	}
+	r, seq, err := derInteger(seq, strict)
+	if err != nil {
+		return nil, err
+	}
+	s, seq, err := derInteger(seq, strict)
+	if err != nil {
+		return nil, err
+	}
+	if len(seq) > 0 {
+		return nil, errors.New("trailing data in sequence")
+	}
+	if r.Sign() <= 0 || r.Cmp(c.Q) >= 0 {
+		return nil, fmt.Errorf("r is out of range%s", verbose("r=%x, Q=%x", r, c.Q))
+	}
+	if s.Sign() <= 0 || s.Cmp(c.Q) >= 0 {
+		return nil, fmt.Errorf("s is out of range%s", verbose("s=%x, Q=%x", s, c.Q))
+	}
+	return rsToSignature(c, r, s), nil
+}
+
+func derTLV(data []byte, tag byte, strict bool) (value, rest []byte, err error) {
+	if len(data) < 2 || data[0] != tag {
+		return nil, nil, fmt.Errorf("expected tag 0x%02x", tag)
+	}
+	l := int(data[1])
+	data = data[2:]
+	if l&0x80 != 0 {
+		// Three length bytes are more than enough for any signature
+		// and 4-byte length may overflow 32-bit int
+		n := l & 0x7F
+		if n == 0 || n > 3 || len(data) < n {
+			return nil, nil, errors.New("invalid length")
+		}
+		l = 0
+		for _, b := range data[:n] {
+			l = l<<8 | int(b)
+		}
+		if strict && (data[0] == 0 || l < 0x80) {
+			return nil, nil, errors.New("non-minimal length")
+		}
+		data = data[n:]
+	}
+	if l > len(data) {
+		return nil, nil, errors.New("truncated data")
+	}
+	return data[:l], data[l:], nil
+}
+
+func derInteger(data []byte, strict bool) (*big.Int, []byte, error) {
+	v, rest, err := derTLV(data, 0x02, strict)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(v) == 0 {
+		return nil, nil, errors.New("empty integer")
+	}
+	if v[0]&0x80 != 0 {
+		return nil, nil, errors.New("negative integer")
+	}
+	if strict && len(v) > 1 && v[0] == 0 && v[1]&0x80 == 0 {
+		return nil, nil, errors.New("non-minimal integer")
+	}
+	return big.NewInt(0).SetBytes(v), rest, nil
+}
@@ -0,0 +1,190 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+)
+
+func TestSignatureDER(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sig, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := MarshalSignatureDER(c, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unmarshal := range []func(*Curve, []byte) ([]byte, error){
+		UnmarshalSignatureDER, UnmarshalSignatureDERLax,
+	} {
+		got, err := unmarshal(c, der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, sig) {
+			t.FailNow()
+		}
+		if _, err = unmarshal(c, append(der, 0)); err == nil {
+			t.FailNow()
+		}
+	}
+
+	// r = 1 with extra leading zero, s = 2 with long form length
+	sloppy := []byte{0x30, 0x08, 0x02, 0x02, 0x00, 0x01, 0x02, 0x81, 0x01, 0x02}
+	if _, err = UnmarshalSignatureDER(c, sloppy); err == nil {
+		t.FailNow()
+	}
+	got, err := UnmarshalSignatureDERLax(c, sloppy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := SignatureToRS(c, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(bigInt1) != 0 || s.Cmp(bigInt2) != 0 {
+		t.FailNow()
+	}
+	if _, err = UnmarshalSignatureDER(c, []byte{0x30, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x02}); err != nil {
+		t.Fatal(err)
+	}
+
+	negative := []byte{0x30, 0x06, 0x02, 0x01, 0x81, 0x02, 0x01, 0x02}
+	if _, err = UnmarshalSignatureDER(c, negative); err == nil {
+		t.FailNow()
+	}
+	if _, err = UnmarshalSignatureDERLax(c, negative); err == nil {
+		t.FailNow()
+	}
+
+	for _, huge := range [][]byte{
+		{0x30, 0x84, 0x80, 0x00, 0x00, 0x00, 0x02, 0x01, 0x01},
+		{0x30, 0x06, 0x02, 0x84, 0x80, 0x00, 0x00, 0x01, 0x01},
+	} {
+		if _, err = UnmarshalSignatureDER(c, huge); err == nil {
+			t.FailNow()
+		}
+		if _, err = UnmarshalSignatureDERLax(c, huge); err == nil {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignatureECDSAStyle(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 64)
+	rand.Read(digest)
+	sig, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := MarshalSignatureECDSAStyle(c, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(der, &rs); err != nil {
+		t.Fatal(err)
+	}
+	r, s, err := SignatureToRS(c, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.R.Cmp(r) != 0 || rs.S.Cmp(s) != 0 {
+		t.Fatal("r is not the first")
+	}
+	got, err := UnmarshalSignatureECDSAStyle(c, der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, sig) {
+		t.FailNow()
+	}
+	if _, err = UnmarshalSignatureECDSAStyle(c, der[:len(der)-1]); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestSignMulti(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.DigestSize())
+		rand.Read(digest)
+		raw, der, err := prv.SignMulti(rand.Reader, digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if valid, err := pub.VerifyDigest(digest, raw); err != nil || !valid {
+			t.FailNow()
+		}
+		fromDER, err := UnmarshalSignatureDER(c, der)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(fromDER, raw) {
+			t.Fatal("encodings differ")
+		}
+		if valid, err := pub.VerifyDigest(digest, fromDER); err != nil || !valid {
+			t.FailNow()
+		}
+		var rs struct{ R, S *big.Int }
+		if _, err = asn1.Unmarshal(der, &rs); err != nil {
+			t.Fatal(err)
+		}
+		r, s, err := SignatureToRS(c, raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rs.R.Cmp(r) != 0 || rs.S.Cmp(s) != 0 {
+			t.FailNow()
+		}
+	}
+	if _, _, err := (&PrivateKey{
+		C:   CurveIdtc26gost341012256paramSetB(),
+		Key: big.NewInt(1),
+	}).SignMulti(rand.Reader, make([]byte, 64)); err == nil {
+		t.FailNow()
+	}
+}
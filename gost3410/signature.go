@@ -0,0 +1,104 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Split raw signature into its r and s components. Signature is
+// BE(s)||BE(r), each of c.PointSize() length. Both values must be
+// within [1, Q).
+func SignatureToRS(c *Curve, sig []byte) (r, s *big.Int, err error) {
+	pointSize := c.PointSize()
+	if len(sig) != 2*pointSize {
+		return nil, nil, fmt.Errorf("gogost/gost3410: len(signature)=%d != %d", len(sig), 2*pointSize)
+	}
+	s = bytes2big(sig[:pointSize])
+	r = bytes2big(sig[pointSize:])
+	if r.Sign() <= 0 || r.Cmp(c.Q) >= 0 {
+		return nil, nil, fmt.Errorf("gogost/gost3410: r is out of range%s", verbose("r=%x, Q=%x", r, c.Q))
+	}
+	if s.Sign() <= 0 || s.Cmp(c.Q) >= 0 {
+		return nil, nil, fmt.Errorf("gogost/gost3410: s is out of range%s", verbose("s=%x, Q=%x", s, c.Q))
+	}
+	return r, s, nil
+}
+
+// Build raw BE(s)||BE(r) signature from its components, that must be
+// within [1, Q).
+func RSToSignature(c *Curve, r, s *big.Int) ([]byte, error) {
+	if r.Sign() <= 0 || r.Cmp(c.Q) >= 0 {
+		return nil, fmt.Errorf("gogost/gost3410.RSToSignature: r is out of range%s", verbose("r=%x, Q=%x", r, c.Q))
+	}
+	if s.Sign() <= 0 || s.Cmp(c.Q) >= 0 {
+		return nil, fmt.Errorf("gogost/gost3410.RSToSignature: s is out of range%s", verbose("s=%x, Q=%x", s, c.Q))
+	}
+	return rsToSignature(c, r, s), nil
+}
+
+// The same as RSToSignature, but without range checks, for already
+// valid components.
+func rsToSignature(c *Curve, r, s *big.Int) []byte {
+	pointSize := c.PointSize()
+	return append(
+		pad(s.Bytes(), pointSize),
+		pad(r.Bytes(), pointSize)...,
+	)
+}
+
+// Swap two PointSize halves of the signature, converting between
+// this package's native BE(s)||BE(r) form and r||s one, that some
+// other implementations use. Signature of invalid length is returned
+// as is.
+func ReverseSignatureHalves(c *Curve, sig []byte) []byte {
+	pointSize := c.PointSize()
+	if len(sig) != 2*pointSize {
+		return sig
+	}
+	return append(
+		append(make([]byte, 0, len(sig)), sig[pointSize:]...),
+		sig[:pointSize]...,
+	)
+}
+
+// Verify the signature either in native s||r or in r||s form.
+func (pub *PublicKey) VerifyEither(digest, signature []byte) (bool, error) {
+	valid, err := pub.VerifyDigest(digest, signature)
+	if err != nil || valid {
+		return valid, err
+	}
+	return pub.VerifyDigest(digest, ReverseSignatureHalves(pub.C, signature))
+}
+
+// Cheaply check that the signature is structurally valid and the
+// public key is on its curve, without any scalar multiplication: it
+// is intended for shedding obviously bad input before VerifyDigest.
+// Signature must be of 2*PointSize length with r and s within [1, Q).
+func PreValidate(pub *PublicKey, sig []byte) error {
+	if pub == nil || pub.C == nil || pub.X == nil || pub.Y == nil {
+		return errors.New("gogost/gost3410.PreValidate: incomplete public key")
+	}
+	if _, _, err := SignatureToRS(pub.C, sig); err != nil {
+		return fmt.Errorf("gogost/gost3410.PreValidate: %w", err)
+	}
+	if !pub.C.IsOnCurve(pub.X, pub.Y) {
+		return fmt.Errorf("gogost/gost3410.PreValidate: public key is not on the curve%s", pub.C.notOnCurve(pub.X, pub.Y))
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func TestSignatureRSRoundTrip(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		digest := make([]byte, c.PointSize())
+		rand.Read(digest)
+		sign, err := prv.SignDigest(digest, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, s, err := SignatureToRS(c, sign)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(rsToSignature(c, r, s), sign) {
+			t.FailNow()
+		}
+		if !bytes.Equal(rsToSignature(c, bigInt1, bigInt2), append(
+			append(make([]byte, c.PointSize()-1), 2),
+			append(make([]byte, c.PointSize()-1), 1)...,
+		)) {
+			t.FailNow()
+		}
+	}
+}
+
+func TestSignatureToRSInvalid(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	if _, _, err := SignatureToRS(c, make([]byte, 63)); err == nil {
+		t.FailNow()
+	}
+	if _, _, err := SignatureToRS(c, make([]byte, 64)); err == nil {
+		t.FailNow()
+	}
+	if _, _, err := SignatureToRS(c, rsToSignature(c, c.Q, bigInt1)); err == nil {
+		t.FailNow()
+	}
+	if _, _, err := SignatureToRS(c, rsToSignature(c, bigInt1, c.Q)); err == nil {
+		t.FailNow()
+	}
+	q1 := big.NewInt(0).Sub(c.Q, bigInt1)
+	if _, _, err := SignatureToRS(c, rsToSignature(c, q1, q1)); err != nil {
+		t.FailNow()
+	}
+}
+
+func TestRecoverKeyFromReusedNonce(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest1 := make([]byte, c.PointSize())
+	digest2 := make([]byte, c.PointSize())
+	rand.Read(digest1)
+	rand.Read(digest2)
+	seed := []byte("reused nonce")
+	sig1, err := prv.SignDigest(digest1, DeterministicReader(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := prv.SignDigest(digest2, DeterministicReader(seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := RecoverKeyFromReusedNonce(c, digest1, sig1, digest2, sig2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Key.Cmp(prv.Key) != 0 {
+		t.FailNow()
+	}
+	sig2, err = prv.SignDigest(digest2, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = RecoverKeyFromReusedNonce(c, digest1, sig1, digest2, sig2); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestVerifyEither(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := prv.PublicKey()
+	digest := make([]byte, 64)
+	rand.Read(digest)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reversed := ReverseSignatureHalves(c, sign)
+	r, s, _ := SignatureToRS(c, sign)
+	if !bytes.Equal(reversed, append(pad(r.Bytes(), 64), pad(s.Bytes(), 64)...)) {
+		t.FailNow()
+	}
+	if !bytes.Equal(ReverseSignatureHalves(c, reversed), sign) {
+		t.FailNow()
+	}
+	if valid, _ := pub.VerifyDigest(digest, reversed); valid {
+		t.FailNow()
+	}
+	for _, sig := range [][]byte{sign, reversed} {
+		valid, err := pub.VerifyEither(digest, sig)
+		if err != nil || !valid {
+			t.FailNow()
+		}
+	}
+	digest[0] ^= 1
+	if valid, _ := pub.VerifyEither(digest, sign); valid {
+		t.FailNow()
+	}
+}
+
+func TestPreValidate(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := make([]byte, 32)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = PreValidate(pub, sign); err != nil {
+		t.Fatal(err)
+	}
+	for name, sig := range map[string][]byte{
+		"short":  sign[1:],
+		"long":   append(append([]byte{}, sign...), 0),
+		"zero r": rsToSignature(c, big.NewInt(0), bigInt1),
+		"zero s": rsToSignature(c, bigInt1, big.NewInt(0)),
+		"big r":  rsToSignature(c, c.Q, bigInt1),
+		"big s":  rsToSignature(c, bigInt1, c.Q),
+	} {
+		if err = PreValidate(pub, sig); err == nil {
+			t.Fatal(name, "signature is accepted")
+		}
+	}
+	offCurve := &PublicKey{C: c, X: pub.X, Y: big.NewInt(0).Add(pub.Y, bigInt1)}
+	if err = PreValidate(offCurve, sign); err == nil {
+		t.Fatal("public key off the curve is accepted")
+	}
+	if err = PreValidate(&PublicKey{C: c}, sign); err == nil {
+		t.Fatal("incomplete public key is accepted")
+	}
+}
+
+func TestRSToSignatureRange(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	q1 := big.NewInt(0).Sub(c.Q, bigInt1)
+	sig, err := RSToSignature(c, q1, bigInt1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, s, err := SignatureToRS(c, sig); err != nil || r.Cmp(q1) != 0 || s.Cmp(bigInt1) != 0 {
+		t.Fatal("round trip", err)
+	}
+	wide := big.NewInt(0).Lsh(bigInt1, uint(8*c.PointSize()))
+	for name, rs := range map[string][2]*big.Int{
+		"zero r": {big.NewInt(0), bigInt1},
+		"zero s": {bigInt1, big.NewInt(0)},
+		"big r":  {c.Q, bigInt1},
+		"big s":  {bigInt1, c.Q},
+		"wide r": {wide, bigInt1},
+		"neg s":  {bigInt1, big.NewInt(-1)},
+	} {
+		if _, err := RSToSignature(c, rs[0], rs[1]); err == nil {
+			t.Fatal(name, "accepted")
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+// GostR3410-2012-PublicKeyParameters from RFC 9215
+type publicKeyParameters struct {
+	PublicKeyParamSet asn1.ObjectIdentifier
+	DigestParamSet    asn1.ObjectIdentifier `asn1:"optional"`
+}
+
+type subjectPublicKeyInfo struct {
+	Algo      pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// Get public key algorithm identifier for the curve.
+// 256-bit curves are treated as 34.10-2012 ones.
+func curveAlgorithmIdentifier(c *Curve) (pkix.AlgorithmIdentifier, error) {
+	var ai pkix.AlgorithmIdentifier
+	if len(c.OID) == 0 {
+		return ai, errors.New("gogost/gost3410: curve has no OID")
+	}
+	params := publicKeyParameters{PublicKeyParamSet: c.OID}
+	if c.Is512() {
+		ai.Algorithm = oidTc26Gost341012512
+	} else {
+		ai.Algorithm = oidTc26Gost341012256
+		if oidHasPrefix(c.OID, oidCryptoProParamSetPrefix) {
+			params.DigestParamSet = oidTc26Gost341112256
+		}
+	}
+	paramsRaw, err := asn1.Marshal(params)
+	if err != nil {
+		return ai, err
+	}
+	ai.Parameters = asn1.RawValue{FullBytes: paramsRaw}
+	return ai, nil
+}
+
+// Resolve the curve from public key algorithm identifier.
+func curveFromAlgorithmIdentifier(ai pkix.AlgorithmIdentifier) (*Curve, error) {
+	if !ai.Algorithm.Equal(oidGostR34102001) &&
+		!ai.Algorithm.Equal(oidTc26Gost341012256) &&
+		!ai.Algorithm.Equal(oidTc26Gost341012512) {
+		return nil, fmt.Errorf("gogost/gost3410: unknown public key algorithm %s", ai.Algorithm)
+	}
+	var params publicKeyParameters
+	rest, err := asn1.Unmarshal(ai.Parameters.FullBytes, &params)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410: invalid public key parameters: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410: trailing data after public key parameters")
+	}
+	c := CurveByOID(params.PublicKeyParamSet)
+	if c == nil {
+		return nil, fmt.Errorf("gogost/gost3410: unknown curve %s", params.PublicKeyParamSet)
+	}
+	if c.Is512() != ai.Algorithm.Equal(oidTc26Gost341012512) {
+		return nil, errors.New("gogost/gost3410: curve does not match public key algorithm")
+	}
+	return c, nil
+}
+
+// Marshal public key into PKIX SubjectPublicKeyInfo DER form,
+// as RFC 9215 describes. Curve must have an OID.
+func MarshalPKIXPublicKey(pub *PublicKey) ([]byte, error) {
+	ai, err := curveAlgorithmIdentifier(pub.C)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalPKIXPublicKey: %w", err)
+	}
+	key, err := asn1.Marshal(pub.Raw())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.MarshalPKIXPublicKey: %w", err)
+	}
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algo:      ai,
+		PublicKey: asn1.BitString{Bytes: key, BitLength: 8 * len(key)},
+	})
+}
+
+// Parse PKIX SubjectPublicKeyInfo DER form.
+func ParsePKIXPublicKey(der []byte) (*PublicKey, error) {
+	var spki subjectPublicKeyInfo
+	rest, err := asn1.Unmarshal(der, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKIXPublicKey: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParsePKIXPublicKey: trailing data")
+	}
+	c, err := curveFromAlgorithmIdentifier(spki.Algo)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKIXPublicKey: %w", err)
+	}
+	var raw []byte
+	rest, err = asn1.Unmarshal(spki.PublicKey.RightAlign(), &raw)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKIXPublicKey: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("gogost/gost3410.ParsePKIXPublicKey: trailing data after key")
+	}
+	pub, err := NewPublicKey(c, raw)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKIXPublicKey: %w", err)
+	}
+	if _, err = c.PointOrder(pub.X, pub.Y); err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ParsePKIXPublicKey: %w", err)
+	}
+	return pub, nil
+}
+
+// Verify signature of the digest with public key in SubjectPublicKeyInfo
+// DER form. Malformed spki gives an error, while mismatched signature
+// just gives false.
+func VerifyWithSPKI(spki, digest, signature []byte) (bool, error) {
+	pub, err := ParsePKIXPublicKey(spki)
+	if err != nil {
+		return false, fmt.Errorf("gogost/gost3410.VerifyWithSPKI: %w", err)
+	}
+	return pub.VerifyDigest(digest, signature)
+}
+
+// Streebog-256 hash of public key's SubjectPublicKeyInfo DER encoding.
+// It is nil if key's curve has no OID.
+func (pub *PublicKey) Fingerprint() []byte {
+	spki, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil
+	}
+	h := gost34112012256.New()
+	h.Write(spki)
+	return h.Sum(nil)
+}
+
+// Colon-separated uppercase hex of the Fingerprint.
+func (pub *PublicKey) FingerprintString() string {
+	fpr := pub.Fingerprint()
+	hexed := make([]string, 0, len(fpr))
+	for _, b := range fpr {
+		hexed = append(hexed, fmt.Sprintf("%02X", b))
+	}
+	return strings.Join(hexed, ":")
+}
+
+// Context, that CrossSign signatures are bound to, see SignContext
+const crossSignContext = "gogost/gost3410 cross-sign"
+
+// Verify the CrossSign signature made by the old key over the new one.
+func VerifyCrossSign(oldPub, newPub *PublicKey, signature []byte) (bool, error) {
+	spki, err := MarshalPKIXPublicKey(newPub)
+	if err != nil {
+		return false, fmt.Errorf("gogost/gost3410.VerifyCrossSign: %w", err)
+	}
+	return oldPub.VerifyContext(spki, []byte(crossSignContext), signature)
+}
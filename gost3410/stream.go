@@ -0,0 +1,84 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+// Get Streebog hash corresponding to the curve's size.
+func curveHash(c *Curve) hash.Hash {
+	// PointSize is always either 32 or 64
+	h, _ := gost34112012256.NewHashBySize(8 * c.PointSize())
+	return h
+}
+
+// Get reversed curveHash's digest, as 34.10 expects it.
+func curveDigest(h hash.Hash) []byte {
+	digest := h.Sum(nil)
+	reverse(digest)
+	return digest
+}
+
+// Incremental verifier, the counterpart of StreamSigner.
+type StreamVerifier struct {
+	pub *PublicKey
+	sig []byte
+	h   hash.Hash
+}
+
+func (pub *PublicKey) NewVerifier(sig []byte) *StreamVerifier {
+	return &StreamVerifier{pub, sig, curveHash(pub.C)}
+}
+
+func (v *StreamVerifier) Write(p []byte) (int, error) {
+	return v.h.Write(p)
+}
+
+// Verify the signature against the written message.
+func (v *StreamVerifier) Verify() (bool, error) {
+	return v.pub.VerifyDigest(curveDigest(v.h), v.sig)
+}
+
+// Get reversed curveHash's digest of BE32(len(context)) || context ||
+// message, binding the context to the signature unambiguously.
+func contextDigest(c *Curve, message, context []byte) ([]byte, error) {
+	if uint64(len(context)) > math.MaxUint32 {
+		return nil, errors.New("gogost/gost3410: context is too long")
+	}
+	h := curveHash(c)
+	var l [4]byte
+	binary.BigEndian.PutUint32(l[:], uint32(len(context)))
+	h.Write(l[:])
+	h.Write(context)
+	h.Write(message)
+	return curveDigest(h), nil
+}
+
+// Verify the signature made with SignContext under the same context.
+func (pub *PublicKey) VerifyContext(message, context, signature []byte) (bool, error) {
+	digest, err := contextDigest(pub.C, message, context)
+	if err != nil {
+		return false, fmt.Errorf("gogost/gost3410.PublicKey.VerifyContext: %w", err)
+	}
+	return pub.VerifyDigest(digest, signature)
+}
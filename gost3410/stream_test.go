@@ -0,0 +1,185 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+func TestStreamSignVerify(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := make([]byte, 4<<20)
+		rand.Read(msg)
+		signer := prv.NewSigner()
+		if _, err = io.CopyBuffer(signer, &onlyReader{msg}, make([]byte, 12345)); err != nil {
+			t.Fatal(err)
+		}
+		sign, err := signer.Sign(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier := pub.NewVerifier(sign)
+		verifier.Write(msg)
+		valid, err := verifier.Verify()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !valid {
+			t.FailNow()
+		}
+		h := curveHash(c)
+		h.Write(msg)
+		digest := h.Sum(nil)
+		reverse(digest)
+		if valid, _ = pub.VerifyDigest(digest, sign); !valid {
+			t.FailNow()
+		}
+		msg[len(msg)/2] ^= 1
+		verifier = pub.NewVerifier(sign)
+		verifier.Write(msg)
+		if valid, _ = verifier.Verify(); valid {
+			t.FailNow()
+		}
+	}
+}
+
+// Hide bytes.Reader's WriterTo, to make io.CopyBuffer use chunks
+type onlyReader struct {
+	data []byte
+}
+
+func (r *onlyReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestSignContext(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("message")
+	sign, err := prv.SignContext(rand.Reader, msg, []byte("protocol A"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid, err := pub.VerifyContext(msg, []byte("protocol A"), sign); err != nil || !valid {
+		t.Fatal(err)
+	}
+	if valid, _ := pub.VerifyContext(msg, []byte("protocol B"), sign); valid {
+		t.Fatal("accepted with another context")
+	}
+	// Context and message boundary shifting must not be ambiguous
+	if valid, _ := pub.VerifyContext([]byte(" Amessage"), []byte("protocol"), sign); valid {
+		t.Fatal("accepted with shifted boundary")
+	}
+	if valid, _ := pub.VerifyContext(msg, nil, sign); valid {
+		t.Fatal("accepted without context")
+	}
+	plain, err := prv.SignContext(rand.Reader, msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if valid, err := pub.VerifyContext(msg, []byte{}, plain); err != nil || !valid {
+		t.Fatal(err)
+	}
+}
+
+func TestStreamSignerHashMismatch(t *testing.T) {
+	msg := []byte("some message")
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := 512
+		if c.Is512() {
+			other = 256
+		}
+		h, err := gost34112012256.NewHashBySize(other)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer := prv.NewSignerHash(h)
+		signer.Write(msg)
+		if _, err = signer.Sign(rand.Reader); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+		// Non-Streebog hash of the same size
+		sameSize := sha256.New()
+		if c.Is512() {
+			sameSize = sha512.New()
+		}
+		signer = prv.NewSignerHash(sameSize)
+		signer.Write(msg)
+		if _, err = signer.Sign(rand.Reader); !errors.Is(err, ErrDigestSize) {
+			t.Fatal(c.Name, err)
+		}
+
+		if h, err = gost34112012256.NewHashBySize(c.BitSize()); err != nil {
+			t.Fatal(err)
+		}
+		h.Write(msg[:4])
+		signer = prv.NewSignerHash(h)
+		signer.Write(msg[4:])
+		sig, err := signer.Sign(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		verifier := pub.NewVerifier(sig)
+		verifier.Write(msg)
+		if valid, err := verifier.Verify(); err != nil || !valid {
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/hitchpock/gogost/v5/internal/gost34112012"
+)
+
+// Incremental signer: message is written to it and then signed.
+// Streebog-256 or -512 is used depending on the curve's size and its
+// digest is reversed, as PrivateKeyReverseDigest does.
+type StreamSigner struct {
+	prv  *PrivateKey
+	h    hash.Hash
+	bits int // Streebog variant of h, zero if it is not Streebog
+}
+
+func (prv *PrivateKey) NewSigner() *StreamSigner {
+	return &StreamSigner{prv, curveHash(prv.C), prv.C.BitSize()}
+}
+
+// Create signer continuing already started Streebog hashing, for
+// example with already written message's prefix. Streebog variant is
+// recorded and Sign checks that it matches the curve's size.
+func (prv *PrivateKey) NewSignerHash(h hash.Hash) *StreamSigner {
+	bits := 0
+	if streebog, ok := h.(*gost34112012.Hash); ok {
+		bits = 8 * streebog.Size()
+	}
+	return &StreamSigner{prv, h, bits}
+}
+
+func (s *StreamSigner) Write(p []byte) (int, error) {
+	return s.h.Write(p)
+}
+
+// Sign the written message. Signer can be further written to.
+func (s *StreamSigner) Sign(rand io.Reader) ([]byte, error) {
+	if s.bits != s.prv.C.BitSize() {
+		return nil, fmt.Errorf(
+			"gogost/gost3410.StreamSigner.Sign: %w: hash is not Streebog-%d",
+			ErrDigestSize, s.prv.C.BitSize(),
+		)
+	}
+	return s.prv.SignDigest(curveDigest(s.h), rand)
+}
+
+// Sign the message bound to the context string, preventing
+// cross-protocol signatures reuse: Streebog of the curve's size is
+// computed over BE32(len(context)) || context || message, so different
+// contexts never give the same hashed data. Verify with VerifyContext.
+func (prv *PrivateKey) SignContext(rand io.Reader, message, context []byte) ([]byte, error) {
+	digest, err := contextDigest(prv.C, message, context)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SignContext: %w", err)
+	}
+	return prv.SignDigest(digest, rand)
+}
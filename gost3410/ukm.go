@@ -13,12 +13,21 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 )
 
+// Maximal UKM width in bytes of all VKO variants: 256-bit one of
+// VKO GOST R 34.10-2012 512-bit.
+const UKMMaxSize = 32
+
 // Unmarshal little-endian UKM value.
 func NewUKM(raw []byte) *big.Int {
 	t := make([]byte, len(raw))
@@ -27,3 +36,28 @@ func NewUKM(raw []byte) *big.Int {
 	}
 	return bytes2big(t)
 }
+
+// Unmarshal little-endian UKM value, like NewUKM does, but reject empty,
+// zero or longer than UKMMaxSize values. The width each VKO variant
+// accepts is also checked by its KEK function.
+func UKMFromBytes(b []byte) (*big.Int, error) {
+	if len(b) == 0 || len(b) > UKMMaxSize {
+		return nil, fmt.Errorf("gogost/gost3410.UKMFromBytes: invalid UKM length %d", len(b))
+	}
+	ukm := NewUKM(b)
+	if ukm.Sign() == 0 {
+		return nil, errors.New("gogost/gost3410.UKMFromBytes: zero UKM")
+	}
+	return ukm, nil
+}
+
+// Check that UKM is within [1, 2^bits), as the VKO variant requires.
+func checkUKM(ukm *big.Int, bits int) error {
+	if ukm.Sign() <= 0 {
+		return errors.New("UKM must be positive")
+	}
+	if ukm.BitLen() > bits {
+		return fmt.Errorf("UKM is %d bits wide, but at most %d are allowed", ukm.BitLen(), bits)
+	}
+	return nil
+}
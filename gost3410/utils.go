@@ -39,3 +39,22 @@ func pointSize(p *big.Int) int {
 	}
 	return 32
 }
+
+// Overwrite secret values memory with zeros, including the unused
+// capacity of their underlying words.
+func zeroize(ints ...*big.Int) {
+	for _, v := range ints {
+		words := v.Bits()
+		words = words[:cap(words)]
+		for i := range words {
+			words[i] = 0
+		}
+		v.SetInt64(0)
+	}
+}
+
+func zeroizeBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
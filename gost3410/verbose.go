@@ -0,0 +1,55 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Include the offending values in errors of curve creation, signature
+// parsing and points checking, to simplify debugging. It is off by
+// default, as values may be sensitive and errors could go to logs. Set
+// it before using the package: it is not protected against concurrent
+// modification.
+var VerboseErrors bool
+
+// Get " (details)" suffix for the error message, if VerboseErrors is set.
+func verbose(format string, args ...interface{}) string {
+	if !VerboseErrors {
+		return ""
+	}
+	return " (" + fmt.Sprintf(format, args...) + ")"
+}
+
+// Get verbose explanation why the point is not on the curve.
+func (c *Curve) notOnCurve(x, y *big.Int) string {
+	if !VerboseErrors {
+		return ""
+	}
+	if x.Sign() < 0 || x.Cmp(c.P) >= 0 || y.Sign() < 0 || y.Cmp(c.P) >= 0 {
+		return verbose("coordinates are not within [0, P): x=%x, y=%x", x, y)
+	}
+	lhs := big.NewInt(0).Mul(y, y)
+	lhs.Mod(lhs, c.P)
+	rhs := big.NewInt(0).Mul(x, x)
+	rhs.Add(rhs, c.A)
+	rhs.Mul(rhs, x)
+	rhs.Add(rhs, c.B)
+	rhs.Mod(rhs, c.P)
+	c.pos(rhs)
+	return verbose("y^2 != x^3+ax+b: x=%x, y=%x, y^2=%x, x^3+ax+b=%x", x, y, lhs, rhs)
+}
@@ -0,0 +1,57 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestVerboseErrors(t *testing.T) {
+	std := CurveIdtc26gost341012256paramSetB()
+	y := big.NewInt(0).Add(std.Y, bigInt1)
+	_, err := NewCurve(std.P, std.Q, std.A, std.B, std.X, y, nil, nil, nil)
+	if !errors.Is(err, ErrInvalidCurveParams) {
+		t.Fatal(err)
+	}
+	if strings.Contains(err.Error(), "y^2") {
+		t.Fatal("verbose details without VerboseErrors")
+	}
+	VerboseErrors = true
+	defer func() { VerboseErrors = false }()
+	_, err = NewCurve(std.P, std.Q, std.A, std.B, std.X, y, nil, nil, nil)
+	if !errors.Is(err, ErrInvalidCurveParams) {
+		t.Fatal(err)
+	}
+	if !strings.Contains(err.Error(), "y^2 != x^3+ax+b") ||
+		!strings.Contains(err.Error(), y.Text(16)) {
+		t.Fatal(err)
+	}
+	_, err = NewCurve(std.P, big.NewInt(0).Lsh(std.Q, 2), std.A, std.B, std.X, std.Y, nil, nil, nil)
+	if !errors.Is(err, ErrInvalidCurveParams) || !strings.Contains(err.Error(), "Co*Q=") {
+		t.Fatal(err)
+	}
+	_, _, err = SignatureToRS(std, rsToSignature(std, big.NewInt(0), bigInt1))
+	if err == nil || !strings.Contains(err.Error(), "r=0") {
+		t.Fatal(err)
+	}
+	_, _, err = SignatureToRS(std, rsToSignature(std, bigInt1, std.Q))
+	if err == nil || !strings.Contains(err.Error(), "s="+std.Q.Text(16)) {
+		t.Fatal(err)
+	}
+}
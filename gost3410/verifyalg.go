@@ -0,0 +1,56 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/hitchpock/gogost/v5/gost28147"
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+	"github.com/hitchpock/gogost/v5/gost341194"
+)
+
+// Verify native form signature of the message, hashing it with the
+// digest algorithm signature algorithm OID implies:
+// GOST R 34.11-94 (CryptoPro parameters) for GOST R 34.10-2001 one,
+// Streebog-256/512 for 34.10-2012 ones. Digest is reversed, as
+// PrivateKeyReverseDigest does. Public key's size must match the OID.
+func VerifyByAlgorithm(algOID asn1.ObjectIdentifier, pub *PublicKey, message, sig []byte) (bool, error) {
+	var h hash.Hash
+	var pointSize int
+	switch {
+	case algOID.Equal(oidGostR341194WithGostR34102001):
+		h = gost341194.New(&gost28147.SboxIdGostR341194CryptoProParamSet)
+		pointSize = 32
+	case algOID.Equal(oidTc26SignWithDigestGost341012256):
+		h = gost34112012256.New()
+		pointSize = 32
+	case algOID.Equal(oidTc26SignWithDigestGost341012512):
+		h = gost34112012512.New()
+		pointSize = 64
+	default:
+		return false, fmt.Errorf("gogost/gost3410.VerifyByAlgorithm: unknown signature algorithm %s", algOID)
+	}
+	if pub.C.PointSize() != pointSize {
+		return false, errors.New("gogost/gost3410.VerifyByAlgorithm: public key size does not match algorithm")
+	}
+	h.Write(message)
+	return pub.VerifyDigest(curveDigest(h), sig)
+}
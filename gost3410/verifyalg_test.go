@@ -0,0 +1,80 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost28147"
+	"github.com/hitchpock/gogost/v5/gost341194"
+)
+
+func TestVerifyByAlgorithm(t *testing.T) {
+	msg := []byte("message to be signed")
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, _ := prv.PublicKey()
+		signer := prv.NewSigner()
+		signer.Write(msg)
+		sig, err := signer.Sign(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		oid, otherOID := oidTc26SignWithDigestGost341012256, oidTc26SignWithDigestGost341012512
+		if c.Is512() {
+			oid, otherOID = otherOID, oid
+		}
+		if ok, err := VerifyByAlgorithm(oid, pub, msg, sig); err != nil || !ok {
+			t.Fatal(c.Name, err)
+		}
+		if ok, err := VerifyByAlgorithm(oid, pub, msg[1:], sig); err != nil || ok {
+			t.Fatal(c.Name, err)
+		}
+		if _, err = VerifyByAlgorithm(otherOID, pub, msg, sig); err == nil {
+			t.Fatal(c.Name)
+		}
+	}
+
+	c := CurveIdGostR34102001CryptoProAParamSet()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := prv.PublicKey()
+	h := gost341194.New(&gost28147.SboxIdGostR341194CryptoProParamSet)
+	h.Write(msg)
+	sig, err := prv.SignDigest(curveDigest(h), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyByAlgorithm(oidGostR341194WithGostR34102001, pub, msg, sig); err != nil || !ok {
+		t.Fatal(err)
+	}
+	if _, err = VerifyByAlgorithm(asn1.ObjectIdentifier{1, 2, 3}, pub, msg, sig); err == nil {
+		t.FailNow()
+	}
+}
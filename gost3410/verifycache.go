@@ -0,0 +1,116 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"container/list"
+	"encoding/binary"
+	"math/big"
+	"sync"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+)
+
+type verifyCacheKey [gost34112012256.Size]byte
+
+type verifyCacheEntry struct {
+	key   verifyCacheKey
+	valid bool
+}
+
+// Bounded LRU cache of signature verification results, useful when
+// the same signed tokens are received repeatedly. Results are keyed by
+// Streebog-256 of the curve parameters, public key, digest and the
+// signature, so different keys never share the entries. Verifications
+// ended with errors are not cached. It is safe for concurrent use.
+type VerifyCache struct {
+	size    int
+	mu      sync.Mutex
+	lru     *list.List
+	entries map[verifyCacheKey]*list.Element
+	verify  func(pub *PublicKey, digest, signature []byte) (bool, error)
+}
+
+// Create the cache holding up to size results.
+func NewVerifyCache(size int) *VerifyCache {
+	if size < 1 {
+		size = 1
+	}
+	return &VerifyCache{
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[verifyCacheKey]*list.Element, size),
+		verify: func(pub *PublicKey, digest, signature []byte) (bool, error) {
+			return pub.VerifyDigest(digest, signature)
+		},
+	}
+}
+
+func verifyCacheKeyOf(pub *PublicKey, digest, signature []byte) (key verifyCacheKey) {
+	h := gost34112012256.New()
+	var l [4]byte
+	write := func(data []byte) {
+		binary.BigEndian.PutUint32(l[:], uint32(len(data)))
+		h.Write(l[:])
+		h.Write(data)
+	}
+	c := pub.C
+	for _, v := range []*big.Int{c.P, c.Q, c.A, c.B, c.X, c.Y} {
+		write(v.Bytes())
+	}
+	write(pub.rawOrZero())
+	write(digest)
+	write(signature)
+	h.Sum(key[:0])
+	return
+}
+
+// Verify the signature, returning the cached result if the very same
+// verification was already done.
+func (vc *VerifyCache) Verify(pub *PublicKey, digest, signature []byte) (bool, error) {
+	key := verifyCacheKeyOf(pub, digest, signature)
+	vc.mu.Lock()
+	if el, ok := vc.entries[key]; ok {
+		vc.lru.MoveToFront(el)
+		valid := el.Value.(*verifyCacheEntry).valid
+		vc.mu.Unlock()
+		return valid, nil
+	}
+	vc.mu.Unlock()
+	valid, err := vc.verify(pub, digest, signature)
+	if err != nil {
+		return false, err
+	}
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	if _, ok := vc.entries[key]; ok {
+		return valid, nil
+	}
+	vc.entries[key] = vc.lru.PushFront(&verifyCacheEntry{key: key, valid: valid})
+	if vc.lru.Len() > vc.size {
+		el := vc.lru.Back()
+		vc.lru.Remove(el)
+		delete(vc.entries, el.Value.(*verifyCacheEntry).key)
+	}
+	return valid, nil
+}
+
+// Get the number of cached results.
+func (vc *VerifyCache) Len() int {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+	return vc.lru.Len()
+}
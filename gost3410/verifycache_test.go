@@ -0,0 +1,88 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestVerifyCache(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	var pubs []*PublicKey
+	var sigs [][]byte
+	digest := make([]byte, 32)
+	digest[0] = 1
+	for i := 0; i < 2; i++ {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := prv.SignDigest(digest, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubs = append(pubs, pub)
+		sigs = append(sigs, sig)
+	}
+	vc := NewVerifyCache(2)
+	var calls int
+	verify := vc.verify
+	vc.verify = func(pub *PublicKey, digest, signature []byte) (bool, error) {
+		calls++
+		return verify(pub, digest, signature)
+	}
+	check := func(pub *PublicKey, sig []byte, valid bool, wantCalls int) {
+		t.Helper()
+		got, err := vc.Verify(pub, digest, sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != valid || calls != wantCalls {
+			t.Fatal(got, calls)
+		}
+	}
+	check(pubs[0], sigs[0], true, 1)
+	check(pubs[0], sigs[0], true, 1)
+	// The same key material by another pointer is a hit
+	check(&PublicKey{C: c, X: pubs[0].X, Y: pubs[0].Y}, sigs[0], true, 1)
+	// Another key is never a hit
+	check(pubs[1], sigs[0], false, 2)
+	check(pubs[1], sigs[0], false, 2)
+	if vc.Len() != 2 {
+		t.FailNow()
+	}
+	// Evicts the least recently used pubs[0] result
+	check(pubs[1], sigs[1], true, 3)
+	if vc.Len() != 2 {
+		t.FailNow()
+	}
+	check(pubs[1], sigs[0], false, 3)
+	check(pubs[0], sigs[0], true, 4)
+	if _, err := vc.Verify(pubs[0], digest, sigs[0][:10]); err == nil {
+		t.FailNow()
+	}
+	if calls != 5 || vc.Len() != 2 {
+		t.FailNow()
+	}
+}
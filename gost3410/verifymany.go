@@ -0,0 +1,155 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Odd multiples 1, 3, ..., 2^(wnafWidth-1)-1 of the point in affine
+// Montgomery form, for the fixed-width arithmetic.
+type jacTable struct {
+	x []fe
+	y []fe
+}
+
+func (c *Curve) newJacTable(f *field, x, y *big.Int) *jacTable {
+	t := newWNAFTable(c, x, y)
+	jt := jacTable{make([]fe, len(t.x)), make([]fe, len(t.y))}
+	for i := range t.x {
+		jt.x[i] = f.fromBig(t.x[i])
+		jt.y[i] = f.fromBig(t.y[i])
+	}
+	return &jt
+}
+
+// Compute d1*T1 + d2*T2 with Shamir's trick over wNAF digits: both
+// multiplications share the same doublings.
+func (c *Curve) jacMult2(
+	f *field, a *fe,
+	d1 *big.Int, t1 *jacTable,
+	d2 *big.Int, t2 *jacTable,
+) (x, y *big.Int, isInfinity bool) {
+	digits := [2][]int{wnaf(d1, wnafWidth), wnaf(d2, wnafWidth)}
+	tables := [2]*jacTable{t1, t2}
+	n := len(digits[0])
+	if len(digits[1]) > n {
+		n = len(digits[1])
+	}
+	var q jacPoint
+	var zero, negY fe
+	isInfinity = true
+	for i := n - 1; i >= 0; i-- {
+		if !isInfinity {
+			isInfinity = c.jacDouble(f, a, &q)
+		}
+		for j := 0; j < 2; j++ {
+			if i >= len(digits[j]) || digits[j][i] == 0 {
+				continue
+			}
+			digit := digits[j][i]
+			k := digit / 2
+			if digit < 0 {
+				k = -k
+			}
+			px, py := &tables[j].x[k], &tables[j].y[k]
+			if digit < 0 {
+				f.sub(&negY, &zero, py)
+				py = &negY
+			}
+			if isInfinity {
+				q = jacPoint{x: *px, y: *py, z: f.one}
+				isInfinity = false
+			} else {
+				isInfinity = c.jacAddAffine(f, a, &q, px, py)
+			}
+		}
+	}
+	if isInfinity {
+		return nil, nil, true
+	}
+	x, y = c.jacToAffine(f, &q)
+	return x, y, false
+}
+
+// Verify many signatures made with the same public key, like
+// VerifyDigest does for each of them. Precomputed tables of both the
+// basic point and the public key are built once for the whole batch and
+// both multiplications of each verification share the same doublings.
+// Result has len(sigs) entries: signatures without corresponding digest
+// or malformed ones are reported as invalid, as all of them are for the
+// identity key.
+func (pub *PublicKey) VerifyMany(digests, sigs [][]byte) []bool {
+	c := pub.C
+	valid := make([]bool, len(sigs))
+	if pub.IsIdentity() {
+		return valid
+	}
+	f, a, ok := c.fieldCtx()
+	if !ok {
+		for i := 0; i < len(sigs) && i < len(digests); i++ {
+			valid[i], _ = pub.VerifyDigest(digests[i], sigs[i])
+		}
+		return valid
+	}
+	tBase := c.newJacTable(f, c.X, c.Y)
+	tPub := c.newJacTable(f, pub.X, pub.Y)
+	z1 := big.NewInt(0)
+	z2 := big.NewInt(0)
+	for i := 0; i < len(sigs) && i < len(digests); i++ {
+		if c.checkDigest(digests[i]) != nil {
+			continue
+		}
+		r, s, err := SignatureToRS(c, sigs[i])
+		if err != nil {
+			continue
+		}
+		v := c.DigestToScalar(digests[i])
+		v.ModInverse(v, c.Q)
+		z1.Mul(s, v)
+		z1.Mod(z1, c.Q)
+		z2.Mul(r, v)
+		z2.Mod(z2, c.Q)
+		z2.Sub(c.Q, z2)
+		x, _, inf := c.jacMult2(f, a, z1, tBase, z2, tPub)
+		if inf {
+			continue
+		}
+		valid[i] = x.Mod(x, c.Q).Cmp(r) == 0
+	}
+	return valid
+}
+
+// Find which of the candidate public keys made the signature: index of
+// the first one it is valid for is returned, or -1 if there is none.
+// Keys with other signature size are skipped, as the signature can not
+// be theirs, other verification errors are returned.
+func VerifyAnyKey(pubs []*PublicKey, digest, sig []byte) (int, error) {
+	for i, pub := range pubs {
+		if len(sig) != pub.C.SignatureSize() {
+			continue
+		}
+		valid, err := pub.VerifyDigest(digest, sig)
+		if err != nil {
+			return -1, fmt.Errorf("gogost/gost3410.VerifyAnyKey: %w", err)
+		}
+		if valid {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
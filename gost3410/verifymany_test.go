@@ -0,0 +1,141 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func verifyManyFixture(tb testing.TB, c *Curve, n int) (*PublicKey, [][]byte, [][]byte) {
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	digests := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		digests[i] = make([]byte, c.PointSize())
+		if _, err = rand.Read(digests[i]); err != nil {
+			tb.Fatal(err)
+		}
+		if sigs[i], err = prv.SignDigest(digests[i], rand.Reader); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return pub, digests, sigs
+}
+
+func TestVerifyMany(t *testing.T) {
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdGostR34102001CryptoProAParamSet(),
+		CurveIdtc26gost341012512paramSetB(),
+	} {
+		pub, digests, sigs := verifyManyFixture(t, c, 8)
+		sigs[2][0] ^= 0x01
+		digests[5][3] ^= 0x01
+		sigs[6] = sigs[6][1:]
+		sigs = append(sigs, sigs[0])
+		valid := pub.VerifyMany(digests, sigs)
+		if len(valid) != len(sigs) {
+			t.FailNow()
+		}
+		for i := range digests {
+			expected, _ := pub.VerifyDigest(digests[i], sigs[i])
+			if valid[i] != expected {
+				t.Fatal(c.Name, i, valid[i])
+			}
+			if valid[i] != (i != 2 && i != 5 && i != 6) {
+				t.Fatal(c.Name, i, valid[i])
+			}
+		}
+		if valid[len(valid)-1] {
+			t.Fatal("signature without digest is valid")
+		}
+	}
+}
+
+func BenchmarkVerifyMany(b *testing.B) {
+	pub, digests, sigs := verifyManyFixture(b, CurveIdtc26gost341012256paramSetA(), 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pub.VerifyMany(digests, sigs)
+	}
+}
+
+func BenchmarkVerifyManyNaive(b *testing.B) {
+	pub, digests, sigs := verifyManyFixture(b, CurveIdtc26gost341012256paramSetA(), 64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range sigs {
+			pub.VerifyDigest(digests[j], sigs[j])
+		}
+	}
+}
+
+func TestVerifyAnyKey(t *testing.T) {
+	var pubs []*PublicKey
+	var prvs []*PrivateKey
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetA(),
+		CurveIdtc26gost341012512paramSetA(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetB(),
+	} {
+		prv, err := GenPrivateKey(c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		prvs = append(prvs, prv)
+		pubs = append(pubs, pub)
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sig, err := prvs[2].SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx, err := VerifyAnyKey(pubs, digest, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != 2 {
+		t.Fatal(idx)
+	}
+	idx, err = VerifyAnyKey(append(pubs[:2:2], pubs[3:]...), digest, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if idx != -1 {
+		t.Fatal(idx)
+	}
+	if idx, err = VerifyAnyKey(nil, digest, sig); idx != -1 || err != nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,128 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+// Sign the digest without PrivateKey, so the test works in
+// gogost_verifyonly builds too.
+func testSign(t *testing.T, c *Curve, d, k *big.Int, digest []byte) []byte {
+	r, _, err := c.ScalarBaseMult(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Mod(r, c.Q)
+	s := big.NewInt(0).Mul(r, d)
+	s.Add(s, big.NewInt(0).Mul(k, c.DigestToScalar(digest)))
+	s.Mod(s, c.Q)
+	return rsToSignature(c, r, s)
+}
+
+func TestVerifyOnlyStdVector(t *testing.T) {
+	c := CurveIdGostR34102001TestParamSet()
+	d := bytes2big([]byte{
+		0x7A, 0x92, 0x9A, 0xDE, 0x78, 0x9B, 0xB9, 0xBE,
+		0x10, 0xED, 0x35, 0x9D, 0xD3, 0x9A, 0x72, 0xC1,
+		0x1B, 0x60, 0x96, 0x1F, 0x49, 0x39, 0x7E, 0xEE,
+		0x1D, 0x19, 0xCE, 0x98, 0x91, 0xEC, 0x3B, 0x28,
+	})
+	dgst := []byte{
+		0x2D, 0xFB, 0xC1, 0xB3, 0x72, 0xD8, 0x9A, 0x11,
+		0x88, 0xC0, 0x9C, 0x52, 0xE0, 0xEE, 0xC6, 0x1F,
+		0xCE, 0x52, 0x03, 0x2A, 0xB1, 0x02, 0x2E, 0x8E,
+		0x67, 0xEC, 0xE6, 0x67, 0x2B, 0x04, 0x3E, 0xE5,
+	}
+	sign := []byte{
+		0x01, 0x45, 0x6C, 0x64, 0xBA, 0x46, 0x42, 0xA1,
+		0x65, 0x3C, 0x23, 0x5A, 0x98, 0xA6, 0x02, 0x49,
+		0xBC, 0xD6, 0xD3, 0xF7, 0x46, 0xB6, 0x31, 0xDF,
+		0x92, 0x80, 0x14, 0xF6, 0xC5, 0xBF, 0x9C, 0x40,
+		0x41, 0xAA, 0x28, 0xD2, 0xF1, 0xAB, 0x14, 0x82,
+		0x80, 0xCD, 0x9E, 0xD5, 0x6F, 0xED, 0xA4, 0x19,
+		0x74, 0x05, 0x35, 0x54, 0xA4, 0x27, 0x67, 0xB8,
+		0x3A, 0xD0, 0x43, 0xFD, 0x39, 0xDC, 0x04, 0x93,
+	}
+	x, y, err := c.ScalarBaseMult(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := &PublicKey{C: c, X: x, Y: y}
+	valid, err := pub.VerifyDigest(dgst, sign)
+	if err != nil || !valid {
+		t.FailNow()
+	}
+	dgst[0] ^= 0x01
+	if valid, _ = pub.VerifyDigest(dgst, sign); valid {
+		t.FailNow()
+	}
+}
+
+func TestVerifyOnly(t *testing.T) {
+	msg := []byte("firmware image")
+	for _, c := range []*Curve{
+		CurveIdtc26gost341012256paramSetB(),
+		CurveIdtc26gost341012512paramSetA(),
+	} {
+		d, err := rand.Int(rand.Reader, c.Q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.Add(d, bigInt1).Mod(d, c.Q)
+		x, y, err := c.ScalarBaseMult(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := NewPublicKey(c, (&PublicKey{C: c, X: x, Y: y}).Raw())
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := curveHash(c)
+		h.Write(msg)
+		sign := testSign(t, c, d, big.NewInt(12345), curveDigest(h))
+
+		verifier := pub.NewVerifier(sign)
+		verifier.Write(msg)
+		if valid, err := verifier.Verify(); err != nil || !valid {
+			t.Fatal(c.Name, err)
+		}
+		spki, err := MarshalPKIXPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParsePKIXPublicKey(spki)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(parsed.Raw(), pub.Raw()) {
+			t.FailNow()
+		}
+		oid := oidTc26SignWithDigestGost341012256
+		if c.Is512() {
+			oid = oidTc26SignWithDigestGost341012512
+		}
+		if valid, err := VerifyByAlgorithm(oid, parsed, msg, sign); err != nil || !valid {
+			t.Fatal(c.Name, err)
+		}
+		if valid, err := VerifyByAlgorithm(oid, parsed, msg[1:], sign); err != nil || valid {
+			t.Fatal(c.Name, err)
+		}
+	}
+}
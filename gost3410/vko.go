@@ -13,25 +13,189 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"sync/atomic"
 )
 
+// Are keys on the curves with the same parameters.
+func SameCurve(a, b interface{ CurveOf() *Curve }) bool {
+	return a.CurveOf().Equal(b.CurveOf())
+}
+
+// Validate peer's public key for the key agreement: it must lie on the
+// curve, must not be the identity and must belong to Q-order subgroup.
+// That protects against invalid-curve and small-subgroup attacks.
+// KEK functions themselves check only that the key lies on the curve.
+func (pub *PublicKey) ForAgreement() (*PublicKey, error) {
+	if pub.C == nil || pub.IsIdentity() {
+		return nil, errors.New("gogost/gost3410.PublicKey.ForAgreement: key is the identity")
+	}
+	if !pub.C.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("gogost/gost3410.PublicKey.ForAgreement: key is not on the curve")
+	}
+	_, _, inf, err := pub.C.ScalarMult(pub.C.Q, pub.X, pub.Y)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PublicKey.ForAgreement: %w", err)
+	}
+	if !inf {
+		return nil, errors.New("gogost/gost3410.PublicKey.ForAgreement: key is not in Q-order subgroup")
+	}
+	return &PublicKey{
+		C: pub.C,
+		X: big.NewInt(0).Set(pub.X),
+		Y: big.NewInt(0).Set(pub.Y),
+	}, nil
+}
+
+// Compute the (UKM*cofactor*prv.Key)*pub shared point marshalled as
+// LE(X)||LE(Y), exactly the way RFC 7836 VKO feeds it to Streebog.
 func (prv *PrivateKey) KEK(pub *PublicKey, ukm *big.Int) ([]byte, error) {
-	keyX, keyY, err := prv.C.Exp(prv.Key, pub.X, pub.Y)
+	key := prv.coScaledKey()
+	defer zeroize(key)
+	kek, err := prv.kek(pub, ukm, key)
 	if err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK: %w", err)
 	}
-	u := big.NewInt(0).Set(ukm).Mul(ukm, prv.C.Co)
-	if u.Cmp(bigInt1) != 0 {
-		keyX, keyY, err = prv.C.Exp(u, keyX, keyY)
+	return kek, nil
+}
+
+// Compute KEK for each of UKMs, like KEK does. The Co*prv.Key*pub point
+// multiplication is done only once for the whole batch, leaving only
+// the UKM multiplication per entry.
+func (prv *PrivateKey) KEKBatch(pub *PublicKey, ukms []*big.Int) ([][]byte, error) {
+	key := prv.coScaledKey()
+	defer zeroize(key)
+	keks, err := prv.kekBatch(pub, ukms, key)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEKBatch: %w", err)
+	}
+	return keks, nil
+}
+
+// Get Co*Key reduced modulo the whole group order Co*Q, so it clears
+// the cofactor component of any curve point exactly like Co*Key does.
+func (prv *PrivateKey) coScaledKey() *big.Int {
+	key := big.NewInt(0).Mul(prv.Key, prv.C.Co)
+	return key.Mod(key, big.NewInt(0).Mul(prv.C.Co, prv.C.Q))
+}
+
+func (prv *PrivateKey) kek(pub *PublicKey, ukm, coKey *big.Int) ([]byte, error) {
+	keyX, keyY, err := prv.sharedPoint(pub, coKey)
+	if err != nil {
+		return nil, err
+	}
+	return prv.kekFromShared(keyX, keyY, ukm)
+}
+
+func (prv *PrivateKey) kekBatch(pub *PublicKey, ukms []*big.Int, coKey *big.Int) ([][]byte, error) {
+	keyX, keyY, err := prv.sharedPoint(pub, coKey)
+	if err != nil {
+		return nil, err
+	}
+	keks := make([][]byte, 0, len(ukms))
+	for i, ukm := range ukms {
+		kek, err := prv.kekFromShared(keyX, keyY, ukm)
 		if err != nil {
-			return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK: %w", err)
+			return nil, fmt.Errorf("ukm %d: %w", i, err)
 		}
+		keks = append(keks, kek)
+	}
+	return keks, nil
+}
+
+// Compute coKey*pub point, common for all UKMs, where coKey is
+// coScaledKey's result.
+func (prv *PrivateKey) sharedPoint(pub *PublicKey, coKey *big.Int) (x, y *big.Int, err error) {
+	if !SameCurve(prv, pub) {
+		return nil, nil, errors.New("keys are on different curves")
+	}
+	if pub.IsIdentity() || !pub.C.IsOnCurve(pub.X, pub.Y) {
+		return nil, nil, errors.New("peer's key is not on the curve")
 	}
-	pk := PublicKey{prv.C, keyX, keyY}
+	x, y, inf, err := prv.C.ScalarMult(coKey, pub.X, pub.Y)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inf {
+		return nil, nil, errors.New("shared point is at infinity")
+	}
+	return x, y, nil
+}
+
+func (prv *PrivateKey) kekFromShared(keyX, keyY, ukm *big.Int) ([]byte, error) {
+	if ukm.Cmp(bigInt1) != 0 {
+		var inf bool
+		var err error
+		keyX, keyY, inf, err = prv.C.ScalarMult(ukm, keyX, keyY)
+		if err != nil {
+			return nil, err
+		}
+		if inf {
+			return nil, errors.New("shared point is at infinity")
+		}
+	}
+	pk := PublicKey{C: prv.C, X: keyX, Y: keyY}
 	return pk.Raw(), nil
 }
+
+// Private key with lazily computed and cached Co*Key mod Co*Q
+// cofactor-scaled key, that KEK functions multiply peer's point by. It
+// is intended for many agreements with the same key, saving the
+// scaling on each of them, and is safe for concurrent use. Like with
+// PrivateKeyBlinded, the cached value is held here, not in PrivateKey,
+// that stays the plain curve and key pair. The cache keeps a copy of
+// Prv.Key it is computed of and is recomputed if Prv.Key is changed.
+// Create it with &PrivateKeyAgreement{Prv: prv}.
+type PrivateKeyAgreement struct {
+	Prv   *PrivateKey
+	coKey atomic.Value // *coScaledKey
+}
+
+type coScaledKey struct {
+	key *big.Int
+	v   *big.Int
+}
+
+func (prv *PrivateKeyAgreement) CurveOf() *Curve {
+	return prv.Prv.C
+}
+
+// Get cached Co*Key mod Co*Q, recomputing it if Prv.Key has changed.
+func (prv *PrivateKeyAgreement) coScaledKey() *big.Int {
+	if k, ok := prv.coKey.Load().(*coScaledKey); ok && k.key.Cmp(prv.Prv.Key) == 0 {
+		return k.v
+	}
+	k := &coScaledKey{
+		key: big.NewInt(0).Set(prv.Prv.Key),
+		v:   prv.Prv.coScaledKey(),
+	}
+	prv.coKey.Store(k)
+	return k.v
+}
+
+// Compute KEK like PrivateKey.KEK does, with the cached scaled key.
+func (prv *PrivateKeyAgreement) KEK(pub *PublicKey, ukm *big.Int) ([]byte, error) {
+	kek, err := prv.Prv.kek(pub, ukm, prv.coScaledKey())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKeyAgreement.KEK: %w", err)
+	}
+	return kek, nil
+}
+
+// Compute KEKs like PrivateKey.KEKBatch does, with the cached scaled
+// key.
+func (prv *PrivateKeyAgreement) KEKBatch(pub *PublicKey, ukms []*big.Int) ([][]byte, error) {
+	keks, err := prv.Prv.kekBatch(pub, ukms, prv.coScaledKey())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKeyAgreement.KEKBatch: %w", err)
+	}
+	return keks, nil
+}
@@ -13,6 +13,9 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
@@ -25,18 +28,44 @@ import (
 )
 
 // RFC 4357 VKO GOST R 34.10-2001 key agreement function.
-// UKM is user keying material, also called VKO-factor.
+// UKM is user keying material, also called VKO-factor, it is 64-bit.
 func (prv *PrivateKey) KEK2001(pub *PublicKey, ukm *big.Int) ([]byte, error) {
-	if prv.C.PointSize() != 32 {
-		return nil, errors.New("gogost/gost3410: KEK2001 is only for 256-bit curves")
-	}
-	key, err := prv.KEK(pub, ukm)
+	key, err := kek2001(prv.C, prv.KEK, pub, ukm)
 	if err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2001: %w", err)
 	}
+	return key, nil
+}
+
+// Same as PrivateKey.KEK2001, with the cached scaled key.
+func (prv *PrivateKeyAgreement) KEK2001(pub *PublicKey, ukm *big.Int) ([]byte, error) {
+	key, err := kek2001(prv.Prv.C, prv.KEK, pub, ukm)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKeyAgreement.KEK2001: %w", err)
+	}
+	return key, nil
+}
+
+// Hash the kek's result with GOST R 34.11-94, checking ukm.
+func kek2001(
+	c *Curve,
+	kek func(*PublicKey, *big.Int) ([]byte, error),
+	pub *PublicKey,
+	ukm *big.Int,
+) ([]byte, error) {
+	if c.Is512() {
+		return nil, errors.New("only 256-bit curves are supported")
+	}
+	if err := checkUKM(ukm, 64); err != nil {
+		return nil, err
+	}
+	key, err := kek(pub, ukm)
+	if err != nil {
+		return nil, err
+	}
 	h := gost341194.New(&gost28147.SboxIdGostR341194CryptoProParamSet)
 	if _, err = h.Write(key); err != nil {
-		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2001: %w", err)
+		return nil, err
 	}
 	return h.Sum(key[:0]), nil
 }
@@ -13,10 +13,18 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash"
+	"io"
 	"math/big"
 
 	"github.com/hitchpock/gogost/v5/gost34112012256"
@@ -24,29 +32,134 @@ import (
 )
 
 // RFC 7836 VKO GOST R 34.10-2012 256-bit key agreement function.
-// UKM is user keying material, also called VKO-factor.
+// UKM is user keying material, also called VKO-factor, it is within
+// [1, 2^(n/2)) for n-bit curve.
 func (prv *PrivateKey) KEK2012256(pub *PublicKey, ukm *big.Int) ([]byte, error) {
-	key, err := prv.KEK(pub, ukm)
+	key, err := kek2012(prv.C, prv.KEK, pub, ukm, gost34112012256.New)
 	if err != nil {
 		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2012256: %w", err)
 	}
-	h := gost34112012256.New()
-	if _, err = h.Write(key); err != nil {
-		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2012256: %w", err)
-	}
-	return h.Sum(key[:0]), nil
+	return key, nil
 }
 
 // RFC 7836 VKO GOST R 34.10-2012 512-bit key agreement function.
-// UKM is user keying material, also called VKO-factor.
+// UKM is user keying material, also called VKO-factor, it is within
+// [1, 2^(n/2)) for n-bit curve.
 func (prv *PrivateKey) KEK2012512(pub *PublicKey, ukm *big.Int) ([]byte, error) {
-	key, err := prv.KEK(pub, ukm)
+	key, err := kek2012(prv.C, prv.KEK, pub, ukm, gost34112012512.New)
 	if err != nil {
-		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2012256: %w", err)
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2012512: %w", err)
 	}
-	h := gost34112012512.New()
+	return key, nil
+}
+
+// Same as PrivateKey.KEK2012256, with the cached scaled key.
+func (prv *PrivateKeyAgreement) KEK2012256(pub *PublicKey, ukm *big.Int) ([]byte, error) {
+	key, err := kek2012(prv.Prv.C, prv.KEK, pub, ukm, gost34112012256.New)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKeyAgreement.KEK2012256: %w", err)
+	}
+	return key, nil
+}
+
+// Same as PrivateKey.KEK2012512, with the cached scaled key.
+func (prv *PrivateKeyAgreement) KEK2012512(pub *PublicKey, ukm *big.Int) ([]byte, error) {
+	key, err := kek2012(prv.Prv.C, prv.KEK, pub, ukm, gost34112012512.New)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKeyAgreement.KEK2012512: %w", err)
+	}
+	return key, nil
+}
+
+// Hash the kek's result with the Streebog of newHash, checking ukm.
+func kek2012(
+	c *Curve,
+	kek func(*PublicKey, *big.Int) ([]byte, error),
+	pub *PublicKey,
+	ukm *big.Int,
+	newHash func() hash.Hash,
+) ([]byte, error) {
+	if err := checkUKM(ukm, c.BitSize()/2); err != nil {
+		return nil, err
+	}
+	key, err := kek(pub, ukm)
+	if err != nil {
+		return nil, err
+	}
+	h := newHash()
 	if _, err = h.Write(key); err != nil {
-		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.KEK2012256: %w", err)
+		return nil, err
 	}
 	return h.Sum(key[:0]), nil
 }
+
+// Maximal SharedKey output length: KDF_TREE with one byte counter.
+const SharedKeyMaxLen = 255 * gost34112012256.Size
+
+// Derive outLen bytes of shared symmetric key. It is KEK2012256 with
+// little-endian ukm, expanded with KDF_TREE_GOSTR3411_2012_256
+// (R 50.1.113-2016) with "kdf tree" label, ukm as a seed, one byte
+// counter and two bytes length. outLen must be in [1, SharedKeyMaxLen].
+func (prv *PrivateKey) SharedKey(pub *PublicKey, ukm []byte, outLen int) ([]byte, error) {
+	if outLen <= 0 || outLen > SharedKeyMaxLen {
+		return nil, errors.New("gogost/gost3410.PrivateKey.SharedKey: invalid output length")
+	}
+	kek, err := prv.KEK2012256(pub, NewUKM(ukm))
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.PrivateKey.SharedKey: %w", err)
+	}
+	mac := hmac.New(gost34112012256.New, kek)
+	l := make([]byte, 2)
+	binary.BigEndian.PutUint16(l, uint16(outLen*8))
+	out := make([]byte, 0, outLen+gost34112012256.Size)
+	for i := 1; len(out) < outLen; i++ {
+		mac.Reset()
+		mac.Write([]byte{byte(i)})
+		mac.Write([]byte("kdf tree"))
+		mac.Write([]byte{0x00})
+		mac.Write(ukm)
+		mac.Write(l)
+		out = mac.Sum(out)
+	}
+	return out[:outLen], nil
+}
+
+// Ephemeral-static key agreement: generate ephemeral key pair on c and
+// compute KEK2012256 with unit UKM against static public key.
+// Returned ephemeral public key has to be sent to the static key's
+// owner, who gets the same shared key with ReceiveEphemeralSharedKey.
+func EphemeralSharedKey(c *Curve, staticPub *PublicKey, rand io.Reader) (ephemeralPub *PublicKey, sharedKey []byte, err error) {
+	if !c.Equal(staticPub.C) {
+		return nil, nil, errors.New("gogost/gost3410.EphemeralSharedKey: static key is on different curve")
+	}
+	prv, err := GenPrivateKey(c, rand)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.EphemeralSharedKey: %w", err)
+	}
+	defer zeroize(prv.Key)
+	ephemeralPub, err = prv.PublicKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.EphemeralSharedKey: %w", err)
+	}
+	sharedKey, err = prv.KEK2012256(staticPub, bigInt1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost3410.EphemeralSharedKey: %w", err)
+	}
+	return ephemeralPub, sharedKey, nil
+}
+
+// Compute the shared key made by EphemeralSharedKey on the static key
+// owner's side. Ephemeral public key must lie on the curve.
+func ReceiveEphemeralSharedKey(staticPrv *PrivateKey, ephemeralPub *PublicKey) ([]byte, error) {
+	if !SameCurve(staticPrv, ephemeralPub) {
+		return nil, errors.New("gogost/gost3410.ReceiveEphemeralSharedKey: keys are on different curves")
+	}
+	if !staticPrv.C.IsOnCurve(ephemeralPub.X, ephemeralPub.Y) {
+		return nil, errors.New("gogost/gost3410.ReceiveEphemeralSharedKey: ephemeral key is not on the curve")
+	}
+	sharedKey, err := staticPrv.KEK2012256(ephemeralPub, bigInt1)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3410.ReceiveEphemeralSharedKey: %w", err)
+	}
+	return sharedKey, nil
+}
@@ -13,13 +13,20 @@
 // You should have received a copy of the GNU General Public License
 // along with this program.  If not, see <http://www.gnu.org/licenses/>.
 
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
 package gost3410
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/hex"
+	"math/big"
 	"testing"
 	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
 )
 
 func TestVKO2012256(t *testing.T) {
@@ -45,6 +52,42 @@ func TestVKO2012256(t *testing.T) {
 	}
 }
 
+// Recompute the RFC 7836 VKO from the shared point explicitly, locking
+// the little-endian X then Y serialization of the hash input.
+func TestVKO2012HashInput(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetA()
+	ukmRaw, _ := hex.DecodeString("1d80603c8544c727")
+	prvRawA, _ := hex.DecodeString("c990ecd972fce84ec4db022778f50fcac726f46708384b8d458304962d7147f8c2db41cef22c90b102f2968404f9b9be6d47c79692d81826b32b8daca43cb667")
+	pubRawB, _ := hex.DecodeString("192fe183b9713a077253c72c8735de2ea42a3dbc66ea317838b65fa32523cd5efca974eda7c863f4954d1147f1f2b25c395fce1c129175e876d132e94ed5a65104883b414c9b592ec4dc84826f07d0b6d9006dda176ce48c391e3f97d102e03bb598bf132a228a45f7201aba08fc524a2d77e43a362ab022ad4028f75bde3b79")
+	kek256, _ := hex.DecodeString("c9a9a77320e2cc559ed72dce6f47e2192ccea95fa648670582c054c0ef36c221")
+	prvA, _ := NewPrivateKey(c, prvRawA)
+	pubB, _ := NewPublicKey(c, pubRawB)
+	u := big.NewInt(0).Mul(prvA.Key, NewUKM(ukmRaw))
+	u.Mod(u, c.Q)
+	x, y, _, err := c.ScalarMult(u, pubB.X, pubB.Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	le := func(v *big.Int) []byte {
+		b := pad(v.Bytes(), c.PointSize())
+		reverse(b)
+		return b
+	}
+	input := append(le(x), le(y)...)
+	kek, err := prvA.KEK(pubB, NewUKM(ukmRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(kek, input) {
+		t.Fatal("KEK is not LE(X)||LE(Y)")
+	}
+	h := gost34112012256.New()
+	h.Write(input)
+	if !bytes.Equal(h.Sum(nil), kek256) {
+		t.Fatal("hash input differs from RFC 7836")
+	}
+}
+
 func TestRandomVKO2012256(t *testing.T) {
 	c := CurveIdtc26gost341012512paramSetA()
 	f := func(prvRaw1 [64]byte, prvRaw2 [64]byte, ukmRaw [8]byte) bool {
@@ -113,3 +156,331 @@ func TestRandomVKO2012512(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestSharedKey(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	prv1, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv2, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub1, _ := prv1.PublicKey()
+	pub2, _ := prv2.PublicKey()
+	ukm := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	for _, outLen := range []int{1, 32, 33, 100, SharedKeyMaxLen} {
+		key1, err := prv1.SharedKey(pub2, ukm, outLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key2, err := prv2.SharedKey(pub1, ukm, outLen)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(key1) != outLen || !bytes.Equal(key1, key2) {
+			t.FailNow()
+		}
+	}
+	// Single block output is the same as the ordinary KDF's one
+	key, _ := prv1.SharedKey(pub2, ukm, 32)
+	kek, _ := prv1.KEK2012256(pub2, NewUKM(ukm))
+	if !bytes.Equal(key, gost34112012256.NewKDF(kek).Derive(nil, []byte("kdf tree"), ukm)) {
+		t.FailNow()
+	}
+	if _, err = prv1.SharedKey(pub2, ukm, 0); err == nil {
+		t.FailNow()
+	}
+	if _, err = prv1.SharedKey(pub2, ukm, SharedKeyMaxLen+1); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestEphemeralSharedKey(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetC()
+	staticPrv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	staticPub, _ := staticPrv.PublicKey()
+	ephemeralPub, key1, err := EphemeralSharedKey(c, staticPub, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key2, err := ReceiveEphemeralSharedKey(staticPrv, ephemeralPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key1) != 32 || !bytes.Equal(key1, key2) {
+		t.FailNow()
+	}
+	ephemeralPub2, key3, err := EphemeralSharedKey(c, staticPub, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ephemeralPub2.Equal(ephemeralPub) || bytes.Equal(key1, key3) {
+		t.FailNow()
+	}
+	bad := &PublicKey{C: c, X: ephemeralPub.X, Y: big.NewInt(0).Add(ephemeralPub.Y, bigInt1)}
+	if _, err = ReceiveEphemeralSharedKey(staticPrv, bad); err == nil {
+		t.FailNow()
+	}
+	if _, _, err = EphemeralSharedKey(CurveIdtc26gost341012256paramSetA(), staticPub, rand.Reader); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestKEKDifferentCurves(t *testing.T) {
+	prv256, err := GenPrivateKey(CurveIdtc26gost341012256paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prv512, err := GenPrivateKey(CurveIdtc26gost341012512paramSetA(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub512, _ := prv512.PublicKey()
+	if SameCurve(prv256, pub512) || !SameCurve(prv512, pub512) {
+		t.FailNow()
+	}
+	if _, err = prv256.KEK2012256(pub512, bigInt1); err == nil {
+		t.FailNow()
+	}
+	if _, err = prv256.SharedKey(pub512, []byte{1}, 32); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestKEKRejectsInfinity(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := NewPrivateKey(c, []byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prv.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = prv.KEK(pub, c.Q); err == nil {
+		t.FailNow()
+	}
+	if _, err = prv.KEK(pub, bigInt1); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKEKBatch(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvPeer, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := prvPeer.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ukms := []*big.Int{bigInt1, big.NewInt(2), NewUKM([]byte("12345678"))}
+	keks, err := prv.KEKBatch(pub, ukms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keks) != len(ukms) {
+		t.FailNow()
+	}
+	for i, ukm := range ukms {
+		kek, err := prv.KEK(pub, ukm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(kek, keks[i]) {
+			t.Fatal("mismatch at", i)
+		}
+	}
+	if keks, err = prv.KEKBatch(pub, nil); err != nil || len(keks) != 0 {
+		t.FailNow()
+	}
+}
+
+func TestForAgreement(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prvPeer, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPeer, err := prvPeer.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	validated, err := pubPeer.ForAgreement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ukm := big.NewInt(12345)
+	kek1, err := prv.KEK(pubPeer, ukm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kek2, err := prv.KEK(validated, ukm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(kek1, kek2) {
+		t.FailNow()
+	}
+
+	offCurve := &PublicKey{C: c, X: pubPeer.X, Y: big.NewInt(0).Add(pubPeer.Y, bigInt1)}
+	if _, err = offCurve.ForAgreement(); err == nil {
+		t.Fatal("off-curve key is accepted")
+	}
+	if _, err = prv.KEK(offCurve, ukm); err == nil {
+		t.Fatal("KEK accepts off-curve key")
+	}
+	if _, err = (&PublicKey{C: c, X: big.NewInt(0), Y: big.NewInt(0)}).ForAgreement(); err == nil {
+		t.Fatal("identity is accepted")
+	}
+
+	x, y := smallOrderPoint(t, c)
+	if _, err = (&PublicKey{C: c, X: x, Y: y}).ForAgreement(); err == nil {
+		t.Fatal("small-subgroup key is accepted")
+	}
+}
+
+func TestUKMWidth(t *testing.T) {
+	if _, err := UKMFromBytes(nil); err == nil {
+		t.Fatal("empty UKM accepted")
+	}
+	if _, err := UKMFromBytes(make([]byte, 8)); err == nil {
+		t.Fatal("zero UKM accepted")
+	}
+	if _, err := UKMFromBytes(make([]byte, UKMMaxSize+1)); err == nil {
+		t.Fatal("too long UKM accepted")
+	}
+	ukm, err := UKMFromBytes([]byte{0x1d, 0x80, 0x60, 0x3c, 0x85, 0x44, 0xc7, 0x27})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ukm.Cmp(big.NewInt(0x27c744853c60801d)) != 0 {
+		t.Fatal("UKM is not little-endian")
+	}
+
+	c256 := CurveIdtc26gost341012256paramSetA()
+	c512 := CurveIdtc26gost341012512paramSetA()
+	for _, tc := range []struct {
+		c    *Curve
+		kek  func(prv *PrivateKey, pub *PublicKey, ukm *big.Int) ([]byte, error)
+		bits int
+	}{
+		{c256, (*PrivateKey).KEK2001, 64},
+		{c256, (*PrivateKey).KEK2012256, 128},
+		{c512, (*PrivateKey).KEK2012256, 256},
+		{c512, (*PrivateKey).KEK2012512, 256},
+	} {
+		prv, err := GenPrivateKey(tc.c, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := prv.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		widest := big.NewInt(0).Lsh(bigInt1, uint(tc.bits))
+		widest.Sub(widest, bigInt1)
+		for _, ukm := range []*big.Int{bigInt1, widest} {
+			if _, err = tc.kek(prv, pub, ukm); err != nil {
+				t.Fatal(tc.bits, err)
+			}
+		}
+		for _, ukm := range []*big.Int{
+			big.NewInt(0),
+			big.NewInt(-1),
+			big.NewInt(0).Lsh(bigInt1, uint(tc.bits)),
+		} {
+			if _, err = tc.kek(prv, pub, ukm); err == nil {
+				t.Fatal(tc.bits, "invalid UKM accepted", ukm)
+			}
+		}
+	}
+}
+
+func TestKEKCoScaledKey(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetA()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := peer.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ukm := big.NewInt(12345)
+	expected := func() []byte {
+		u := big.NewInt(0).Mul(ukm, c.Co)
+		u.Mul(u, prv.Key)
+		x, y, _, err := c.ScalarMult(u, pub.X, pub.Y)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return (&PublicKey{C: c, X: x, Y: y}).Raw()
+	}
+	agr := &PrivateKeyAgreement{Prv: prv}
+	cached := func() *big.Int {
+		return agr.coKey.Load().(*coScaledKey).v
+	}
+	kek1, err := agr.KEK(pub, ukm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coKey := cached()
+	keks, err := agr.KEKBatch(pub, []*big.Int{ukm, ukm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached() != coKey {
+		t.Fatal("cached scaled key is not reused")
+	}
+	if !bytes.Equal(kek1, expected()) || !bytes.Equal(keks[0], kek1) || !bytes.Equal(keks[1], kek1) {
+		t.Fatal("cached KEK differs")
+	}
+	if kek, err := prv.KEK(pub, ukm); err != nil || !bytes.Equal(kek, kek1) {
+		t.Fatal("uncached KEK differs")
+	}
+	kek2012, err := agr.KEK2012256(pub, ukm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kek, err := prv.KEK2012256(pub, ukm); err != nil || !bytes.Equal(kek, kek2012) {
+		t.Fatal("cached KEK2012256 differs")
+	}
+	if cached() != coKey {
+		t.Fatal("cached scaled key is not reused")
+	}
+	prv.Key.Add(prv.Key, bigInt1)
+	kek3, err := agr.KEK(pub, ukm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached() == coKey {
+		t.Fatal("cache is not invalidated")
+	}
+	if bytes.Equal(kek3, kek1) || !bytes.Equal(kek3, expected()) {
+		t.Fatal("changed key is not used")
+	}
+}
@@ -0,0 +1,115 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3410
+
+import (
+	"math/big"
+)
+
+// wNAF window width used for public key precomputation
+const wnafWidth = 5
+
+// Odd multiples 1, 3, ..., 2^(wnafWidth-1)-1 of the point
+type wnafTable struct {
+	x []*big.Int
+	y []*big.Int
+}
+
+func newWNAFTable(c *Curve, x, y *big.Int) *wnafTable {
+	n := 1 << (wnafWidth - 2)
+	t := wnafTable{make([]*big.Int, n), make([]*big.Int, n)}
+	t.x[0] = big.NewInt(0).Set(x)
+	t.y[0] = big.NewInt(0).Set(y)
+	dblX := big.NewInt(0).Set(x)
+	dblY := big.NewInt(0).Set(y)
+	c.add(dblX, dblY, dblX, dblY)
+	for i := 1; i < n; i++ {
+		t.x[i] = big.NewInt(0).Set(t.x[i-1])
+		t.y[i] = big.NewInt(0).Set(t.y[i-1])
+		c.add(t.x[i], t.y[i], dblX, dblY)
+	}
+	return &t
+}
+
+// Get width-w non-adjacent form of non-negative k, least significant
+// digit first. Non-zero digits are odd and within (-2^(w-1), 2^(w-1)).
+func wnaf(k *big.Int, w uint) []int {
+	d := big.NewInt(0).Set(k)
+	digits := make([]int, 0, d.BitLen()+1)
+	var t big.Int
+	for d.Sign() > 0 {
+		digit := 0
+		if d.Bit(0) == 1 {
+			digit = int(d.Uint64() & (1<<w - 1))
+			if digit >= 1<<(w-1) {
+				digit -= 1 << w
+			}
+			d.Sub(d, t.SetInt64(int64(digit)))
+		}
+		digits = append(digits, digit)
+		d.Rsh(d, 1)
+	}
+	return digits
+}
+
+// Multiply the table's point by non-negative degree.
+func (t *wnafTable) mult(c *Curve, degree *big.Int) (x, y *big.Int, isInfinity bool) {
+	digits := wnaf(degree, wnafWidth)
+	x = big.NewInt(0)
+	y = big.NewInt(0)
+	negY := big.NewInt(0)
+	isInfinity = true
+	for i := len(digits) - 1; i >= 0; i-- {
+		isInfinity = c.addInf(x, y, isInfinity, x, y, isInfinity)
+		digit := digits[i]
+		if digit > 0 {
+			isInfinity = c.addInf(x, y, isInfinity, t.x[digit/2], t.y[digit/2], false)
+		} else if digit < 0 {
+			negY.Sub(c.P, t.y[-digit/2])
+			isInfinity = c.addInf(x, y, isInfinity, t.x[-digit/2], negY, false)
+		}
+	}
+	if isInfinity {
+		return nil, nil, true
+	}
+	return x, y, false
+}
+
+// Public key with wNAF precomputation table, built with Precompute.
+// It is intended for verifying many signatures made with the same key
+// and is safe for concurrent use. Pub must not be modified.
+type PublicKeyPrecomputed struct {
+	Pub   *PublicKey
+	table *wnafTable
+}
+
+// Build wNAF precomputation table for the key, that is used by
+// returned key's VerifyDigest to speed up verification of many
+// signatures. pub itself is left intact. There is no table for the
+// identity key, verification with which fails the same way as with
+// PublicKey.VerifyDigest.
+func (pub *PublicKey) Precompute() *PublicKeyPrecomputed {
+	if pub.IsIdentity() {
+		return &PublicKeyPrecomputed{Pub: pub}
+	}
+	return &PublicKeyPrecomputed{Pub: pub, table: newWNAFTable(pub.C, pub.X, pub.Y)}
+}
+
+// Verify the signature of the digest, like PublicKey.VerifyDigest does.
+func (pub *PublicKeyPrecomputed) VerifyDigest(digest, signature []byte) (bool, error) {
+	ok, _, err := pub.Pub.verify(pub.table, digest, signature)
+	return ok, err
+}
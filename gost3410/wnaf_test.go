@@ -0,0 +1,109 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build !gogost_verifyonly
+// +build !gogost_verifyonly
+
+package gost3410
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sync"
+	"testing"
+	"testing/quick"
+)
+
+func TestWNAFMult(t *testing.T) {
+	c := CurveIdtc26gost341012256paramSetB()
+	table := newWNAFTable(c, c.X, c.Y)
+	f := func(raw [32]byte) bool {
+		degree := big.NewInt(0).SetBytes(raw[:])
+		degree.Mod(degree, c.Q)
+		if degree.Sign() == 0 {
+			return true
+		}
+		x, y, isInfinity := table.mult(c, degree)
+		if isInfinity {
+			return false
+		}
+		expectedX, expectedY, _ := c.Exp(degree, c.X, c.Y)
+		return x.Cmp(expectedX) == 0 && y.Cmp(expectedY) == 0
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+	if _, _, isInfinity := table.mult(c, c.Q); !isInfinity {
+		t.FailNow()
+	}
+}
+
+func TestPrecomputedVerify(t *testing.T) {
+	c := CurveIdtc26gost341012512paramSetB()
+	prv, err := GenPrivateKey(c, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := prv.PublicKey()
+	digest := make([]byte, 64)
+	rand.Read(digest)
+	sign, err := prv.SignDigest(digest, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPrecomp := pub.Precompute()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if valid, err := pubPrecomp.VerifyDigest(digest, sign); err != nil || !valid {
+				t.Error("verification failed")
+			}
+		}()
+	}
+	wg.Wait()
+	digest[0] ^= 1
+	if valid, _ := pubPrecomp.VerifyDigest(digest, sign); valid {
+		t.FailNow()
+	}
+}
+
+func benchmarkVerify(b *testing.B, precompute bool) {
+	c := CurveIdtc26gost341012256paramSetB()
+	prv, _ := GenPrivateKey(c, rand.Reader)
+	pub, _ := prv.PublicKey()
+	var verifier interface {
+		VerifyDigest(digest, signature []byte) (bool, error)
+	} = pub
+	if precompute {
+		verifier = pub.Precompute()
+	}
+	digest := make([]byte, 32)
+	rand.Read(digest)
+	sign, _ := prv.SignDigest(digest, rand.Reader)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		verifier.VerifyDigest(digest, sign)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	benchmarkVerify(b, false)
+}
+
+func BenchmarkVerifyPrecomputed(b *testing.B) {
+	benchmarkVerify(b, true)
+}
@@ -0,0 +1,32 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"fmt"
+	"hash"
+
+	"github.com/hitchpock/gogost/v5/internal/gost34112012"
+)
+
+// Get Streebog hash of the specified digest size in bits: either 256
+// or 512.
+func NewHashBySize(bits int) (hash.Hash, error) {
+	if bits != 256 && bits != 512 {
+		return nil, fmt.Errorf("gogost/gost34112012256.NewHashBySize: unsupported size %d", bits)
+	}
+	return gost34112012.New(bits / 8), nil
+}
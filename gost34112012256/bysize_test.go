@@ -0,0 +1,49 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"bytes"
+	"hash"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+)
+
+func TestNewHashBySize(t *testing.T) {
+	for bits, ref := range map[int]hash.Hash{
+		256: New(),
+		512: gost34112012512.New(),
+	} {
+		h, err := NewHashBySize(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Size() != bits/8 {
+			t.Fatal(bits, h.Size())
+		}
+		h.Write([]byte("data"))
+		ref.Write([]byte("data"))
+		if !bytes.Equal(h.Sum(nil), ref.Sum(nil)) {
+			t.Fatal(bits)
+		}
+	}
+	for _, bits := range []int{0, 128, 384, 1024} {
+		if _, err := NewHashBySize(bits); err == nil {
+			t.Fatal(bits)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import "encoding/binary"
+
+// Expand seed to outLen bytes by concatenating
+// Streebog-256(seed || BE32(counter)) blocks, counter starting from 1.
+// It is a simple deterministic length extender, not a standardized
+// KDF: use KDF or KDF_TREE when key derivation is required.
+func Expand(seed []byte, outLen int) []byte {
+	out := make([]byte, 0, outLen+Size)
+	h := New()
+	var ctr [4]byte
+	for i := uint32(1); len(out) < outLen; i++ {
+		binary.BigEndian.PutUint32(ctr[:], i)
+		h.Reset()
+		h.Write(seed)
+		h.Write(ctr[:])
+		out = h.Sum(out)
+	}
+	return out[:outLen]
+}
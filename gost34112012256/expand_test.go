@@ -0,0 +1,46 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+)
+
+func TestExpand(t *testing.T) {
+	f := func(seed []byte) bool {
+		long := Expand(seed, 1000)
+		if len(long) != 1000 || !bytes.Equal(long, Expand(seed, 1000)) {
+			return false
+		}
+		for _, l := range []int{0, 1, 31, 32, 33, 64, 999} {
+			if !bytes.Equal(Expand(seed, l), long[:l]) {
+				return false
+			}
+		}
+		h := New()
+		h.Write(seed)
+		h.Write([]byte{0, 0, 0, 1})
+		return bytes.Equal(h.Sum(nil), long[:Size])
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(Expand([]byte("a"), 32), Expand([]byte("b"), 32)) {
+		t.FailNow()
+	}
+}
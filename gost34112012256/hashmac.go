@@ -0,0 +1,54 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"crypto/hmac"
+	"fmt"
+	"hash"
+)
+
+type hashMAC struct {
+	hash.Hash
+	mac hash.Hash
+}
+
+func (h *hashMAC) Write(p []byte) (int, error) {
+	h.mac.Write(p)
+	return h.Hash.Write(p)
+}
+
+func (h *hashMAC) Reset() {
+	h.Hash.Reset()
+	h.mac.Reset()
+}
+
+// Create writer computing both Streebog digest of the specified size
+// (see NewHashBySize) and HMAC-Streebog of the same size in one pass.
+// Hash's Sum gives the digest and returned function gives the HMAC of
+// the data written so far.
+func NewHashMAC(key []byte, bits int) (hash.Hash, func() []byte, error) {
+	h, err := NewHashBySize(bits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gogost/gost34112012256.NewHashMAC: unsupported size %d", bits)
+	}
+	mac := hmac.New(func() hash.Hash {
+		h, _ := NewHashBySize(bits)
+		return h
+	}, key)
+	hm := hashMAC{Hash: h, mac: mac}
+	return &hm, func() []byte { return mac.Sum(nil) }, nil
+}
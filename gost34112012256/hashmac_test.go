@@ -0,0 +1,62 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"hash"
+	"testing"
+)
+
+func TestNewHashMAC(t *testing.T) {
+	key := []byte("log chain key")
+	for _, bits := range []int{256, 512} {
+		h, macSum, err := NewHashMAC(key, bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		records := [][]byte{[]byte("first record"), []byte("second one")}
+		ref, _ := NewHashBySize(bits)
+		refMAC := hmac.New(func() hash.Hash {
+			h, _ := NewHashBySize(bits)
+			return h
+		}, key)
+		for _, rec := range records {
+			h.Write(rec)
+			ref.Write(rec)
+			refMAC.Write(rec)
+		}
+		if !bytes.Equal(h.Sum(nil), ref.Sum(nil)) {
+			t.Fatal(bits, "digest mismatch")
+		}
+		if !bytes.Equal(macSum(), refMAC.Sum(nil)) {
+			t.Fatal(bits, "HMAC mismatch")
+		}
+		h.Reset()
+		ref.Reset()
+		refMAC.Reset()
+		h.Write(records[0])
+		ref.Write(records[0])
+		refMAC.Write(records[0])
+		if !bytes.Equal(h.Sum(nil), ref.Sum(nil)) || !bytes.Equal(macSum(), refMAC.Sum(nil)) {
+			t.Fatal(bits, "mismatch after Reset")
+		}
+	}
+	if _, _, err := NewHashMAC(key, 384); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,129 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+	"github.com/hitchpock/gogost/v5/gost3413"
+)
+
+// TLS 1.2 CTR_OMAC record protection (RFC 9189): both encryption and
+// MAC keys are rotated with TLSTREE depending on the record's sequence
+// number, record is MACed with OMAC and then encrypted with CTR.
+type TLSRecordCipher struct {
+	newCipher func(key []byte) cipher.Block
+	blockSize int
+	encTree   *TLSTree
+	macTree   *TLSTree
+	iv        uint64
+}
+
+// Create CTR_OMAC record cipher. cipherName is either "magma" or
+// "kuznyechik". rootKey is K_ENC||K_MAC||IV, where both keys are 32
+// bytes long and IV is the half of cipher's block size.
+func NewTLSRecordCipher(rootKey []byte, cipherName string) (*TLSRecordCipher, error) {
+	c := TLSRecordCipher{}
+	var params TLSTreeParams
+	switch cipherName {
+	case "magma":
+		params = TLSGOSTR341112256WithMagmaCTROMAC
+		c.blockSize = gost341264.BlockSize
+		c.newCipher = func(key []byte) cipher.Block {
+			return gost341264.NewCipher(key)
+		}
+	case "kuznyechik":
+		params = TLSGOSTR341112256WithKuznyechikCTROMAC
+		c.blockSize = gost3412128.BlockSize
+		c.newCipher = func(key []byte) cipher.Block {
+			return gost3412128.NewCipher(key)
+		}
+	default:
+		return nil, fmt.Errorf("gogost/gost34112012256: unknown cipher %q", cipherName)
+	}
+	if len(rootKey) != 2*Size+c.blockSize/2 {
+		return nil, fmt.Errorf(
+			"gogost/gost34112012256: len(rootKey) != %d",
+			2*Size+c.blockSize/2,
+		)
+	}
+	c.encTree = NewTLSTree(params, rootKey[:Size])
+	c.macTree = NewTLSTree(params, rootKey[Size:2*Size])
+	iv := make([]byte, 8)
+	copy(iv[8-c.blockSize/2:], rootKey[2*Size:])
+	c.iv = binary.BigEndian.Uint64(iv)
+	return &c, nil
+}
+
+// Get MAC's length appended to the plaintext.
+func (c *TLSRecordCipher) Overhead() int {
+	return c.blockSize
+}
+
+func (c *TLSRecordCipher) stream(seq uint64) cipher.Stream {
+	key, _ := c.encTree.DeriveCached(seq)
+	// IV_seqnum = (IV + seqnum) mod 2^(n/2), followed by zero half-block
+	ivSeq := make([]byte, 8)
+	binary.BigEndian.PutUint64(ivSeq, c.iv+seq)
+	iv := make([]byte, c.blockSize)
+	copy(iv, ivSeq[8-c.blockSize/2:])
+	return cipher.NewCTR(c.newCipher(key), iv)
+}
+
+func (c *TLSRecordCipher) mac(seq uint64, header, plaintext []byte) []byte {
+	key, _ := c.macTree.DeriveCached(seq)
+	m, err := gost3413.NewMAC(c.newCipher(key), c.blockSize)
+	if err != nil {
+		panic(err)
+	}
+	seqRaw := make([]byte, 8)
+	binary.BigEndian.PutUint64(seqRaw, seq)
+	m.Write(seqRaw)
+	m.Write(header)
+	m.Write(plaintext)
+	return m.Sum(nil)
+}
+
+// Protect the record with seq sequence number. header (type, version
+// and length fields) is authenticated, but not encrypted.
+func (c *TLSRecordCipher) Seal(seq uint64, header, plaintext []byte) []byte {
+	ct := make([]byte, len(plaintext)+c.blockSize)
+	copy(ct, plaintext)
+	copy(ct[len(plaintext):], c.mac(seq, header, plaintext))
+	c.stream(seq).XORKeyStream(ct, ct)
+	return ct
+}
+
+// Decrypt and authenticate the record made by Seal.
+func (c *TLSRecordCipher) Open(seq uint64, header, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.blockSize {
+		return nil, errors.New("gogost/gost34112012256: ciphertext is too short")
+	}
+	pt := make([]byte, len(ciphertext))
+	c.stream(seq).XORKeyStream(pt, ciphertext)
+	tag := pt[len(pt)-c.blockSize:]
+	pt = pt[:len(pt)-c.blockSize]
+	if subtle.ConstantTimeCompare(tag, c.mac(seq, header, pt)) != 1 {
+		return nil, errors.New("gogost/gost34112012256: record authentication failed")
+	}
+	return pt, nil
+}
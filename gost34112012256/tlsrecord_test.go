@@ -0,0 +1,75 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestTLSRecordCipherRoundTrip(t *testing.T) {
+	for _, name := range []string{"magma", "kuznyechik"} {
+		blockSize := 8
+		if name == "kuznyechik" {
+			blockSize = 16
+		}
+		rootKey := make([]byte, 2*Size+blockSize/2)
+		rand.Read(rootKey)
+		sealer, err := NewTLSRecordCipher(rootKey, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opener, err := NewTLSRecordCipher(rootKey, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		header := []byte{0x17, 0x03, 0x03, 0x00, 0x05}
+		pt := []byte("hello")
+		// 63->64 crosses Kuznyechik's, 4095->4096 Magma's
+		// TLSTREE lowest level boundary
+		for _, seq := range []uint64{0, 1, 63, 64, 4095, 4096} {
+			ct := sealer.Seal(seq, header, pt)
+			if len(ct) != len(pt)+sealer.Overhead() {
+				t.FailNow()
+			}
+			got, err := opener.Open(seq, header, ct)
+			if err != nil {
+				t.Fatal(name, seq, err)
+			}
+			if !bytes.Equal(got, pt) {
+				t.FailNow()
+			}
+			if _, err = opener.Open(seq+1, header, ct); err == nil {
+				t.Fatal("wrong seq accepted")
+			}
+			header[0] ^= 1
+			if _, err = opener.Open(seq, header, ct); err == nil {
+				t.Fatal("wrong header accepted")
+			}
+			header[0] ^= 1
+		}
+	}
+}
+
+func TestTLSRecordCipherInvalid(t *testing.T) {
+	if _, err := NewTLSRecordCipher(make([]byte, 2*Size+4), "aes"); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewTLSRecordCipher(make([]byte, 2*Size), "magma"); err == nil {
+		t.FailNow()
+	}
+}
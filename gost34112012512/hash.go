@@ -37,3 +37,17 @@ func init() {
 func New() hash.Hash {
 	return gost34112012.New(64)
 }
+
+// Streebog's g_N compression function operating on 512-bit blocks:
+// h = g_N(h, m), where N is the number of already processed message
+// bits as 512-bit little-endian integer. It is the same for both 256-
+// and 512-bit hashes, which differ only in IV (all 0x01 or 0x00 bytes)
+// and output truncation.
+//
+// This is a low-level API for building custom constructions.
+// Compression function alone is not a hash: it does no padding, length
+// and checksum finalization, and misuse easily leads to insecure
+// results. Use New unless you know exactly what you are doing.
+func Compress(h, m, n *[BlockSize]byte) {
+	gost34112012.Compress(h, m, n)
+}
@@ -0,0 +1,95 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012512
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// scrypt (RFC 7914) password based key derivation function with GOST
+// primitives instead of SHA-256 and Salsa20/8: PBKDF2 uses
+// HMAC-Streebog-512 and BlockMix uses Streebog's g_0 compression
+// function with zero chaining value as the H hash function. Its output
+// therefore differs from RFC 7914 one. N is the CPU/memory cost and
+// must be a power of two greater than 1, r is the block size, p is the
+// parallelization. As in RFC 7914, r*p must be less than 2^30.
+// 128*N*r bytes of memory are used.
+func ScryptGOST(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	if N <= 1 || N&(N-1) != 0 {
+		return nil, errors.New("gogost/gost34112012512.ScryptGOST: N must be > 1 and a power of 2")
+	}
+	if r <= 0 || p <= 0 || keyLen <= 0 {
+		return nil, errors.New("gogost/gost34112012512.ScryptGOST: r, p and keyLen must be positive")
+	}
+	if uint64(r)*uint64(p) >= 1<<30 ||
+		r > math.MaxInt/128/p ||
+		r > math.MaxInt/256 ||
+		N > math.MaxInt/128/r {
+		return nil, errors.New("gogost/gost34112012512.ScryptGOST: parameters are too large")
+	}
+	b := pbkdf2.Key(password, salt, 1, p*128*r, New)
+	v := make([]byte, 128*r*N)
+	for i := 0; i < p; i++ {
+		scryptROMix(b[i*128*r:(i+1)*128*r], v, N)
+	}
+	return pbkdf2.Key(password, b, 1, keyLen, New), nil
+}
+
+func scryptH(x *[BlockSize]byte) {
+	var h, n [BlockSize]byte
+	Compress(&h, x, &n)
+	*x = h
+}
+
+// BlockMix over 2*r 64-byte blocks of b, placing result in y.
+func scryptBlockMix(y, b []byte) {
+	blocks := len(b) / BlockSize
+	var x [BlockSize]byte
+	copy(x[:], b[len(b)-BlockSize:])
+	for i := 0; i < blocks; i++ {
+		for j := 0; j < BlockSize; j++ {
+			x[j] ^= b[i*BlockSize+j]
+		}
+		scryptH(&x)
+		// Even blocks go to the first half, odd ones to the second
+		copy(y[(i/2+(i%2)*blocks/2)*BlockSize:], x[:])
+	}
+}
+
+func scryptROMix(b, v []byte, n int) {
+	size := len(b)
+	x := make([]byte, size)
+	y := make([]byte, size)
+	copy(x, b)
+	for i := 0; i < n; i++ {
+		copy(v[i*size:], x)
+		scryptBlockMix(y, x)
+		x, y = y, x
+	}
+	for i := 0; i < n; i++ {
+		j := int(binary.LittleEndian.Uint64(x[size-BlockSize:]) & uint64(n-1))
+		for k := 0; k < size; k++ {
+			x[k] ^= v[j*size+k]
+		}
+		scryptBlockMix(y, x)
+		x, y = y, x
+	}
+	copy(b, x)
+}
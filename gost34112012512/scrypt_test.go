@@ -0,0 +1,82 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost34112012512
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestScryptGOST(t *testing.T) {
+	password := []byte("password")
+	salt := []byte("NaCl")
+	key1, err := ScryptGOST(password, salt, 256, 8, 2, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key1) != 64 {
+		t.FailNow()
+	}
+	key2, err := ScryptGOST(password, salt, 256, 8, 2, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(key1, key2) {
+		t.Fatal("not deterministic")
+	}
+	for _, key := range [][]byte{
+		mustScrypt(t, []byte("passwore"), salt, 256, 8, 2),
+		mustScrypt(t, password, []byte("NaCm"), 256, 8, 2),
+		mustScrypt(t, password, salt, 512, 8, 2),
+		mustScrypt(t, password, salt, 256, 4, 2),
+		mustScrypt(t, password, salt, 256, 8, 1),
+	} {
+		if bytes.Equal(key, key1) {
+			t.Fatal("parameters do not affect the key")
+		}
+	}
+	short, err := ScryptGOST(password, salt, 256, 8, 2, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(short, key1[:16]) {
+		t.FailNow()
+	}
+}
+
+func mustScrypt(t *testing.T, password, salt []byte, N, r, p int) []byte {
+	key, err := ScryptGOST(password, salt, N, r, p, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestScryptGOSTParams(t *testing.T) {
+	for _, params := range [][4]int{
+		{0, 8, 1, 32},
+		{1, 8, 1, 32},
+		{1000, 8, 1, 32},
+		{1024, 0, 1, 32},
+		{1024, 8, 0, 32},
+		{1024, 8, 1, 0},
+		{1024, 1 << 15, 1 << 15, 32},
+	} {
+		if _, err := ScryptGOST(nil, nil, params[0], params[1], params[2], params[3]); err == nil {
+			t.Fatal("accepted", params)
+		}
+	}
+}
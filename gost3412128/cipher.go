@@ -16,11 +16,20 @@
 // GOST 34.12-2015 128-bit (Кузнечик (Kuznechik)) block cipher.
 package gost3412128
 
+import "strconv"
+
 const (
 	BlockSize = 16
 	KeySize   = 32
 )
 
+// Error, NewCipher panics with, when key has wrong length.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "gogost/gost3412128: invalid key size " + strconv.Itoa(int(k))
+}
+
 var (
 	lc [BlockSize]byte = [BlockSize]byte{
 		148, 32, 133, 16, 194, 192, 1, 251, 1, 192, 194, 16,
@@ -188,18 +197,24 @@ func init() {
 }
 
 type Cipher struct {
-	ks [10][BlockSize]byte
+	ks       [10][BlockSize]byte
+	zeroized bool
 }
 
 func (c *Cipher) BlockSize() int {
 	return BlockSize
 }
 
+// Create new cipher with KeySize-long key. It panics with KeySizeError
+// if key has wrong length.
 func NewCipher(key []byte) *Cipher {
 	if len(key) != KeySize {
-		panic("invalid key size")
+		panic(KeySizeError(len(key)))
 	}
-	var ks [10][BlockSize]byte
+	return &Cipher{ks: expandKey(key, s, l)}
+}
+
+func expandKey(key []byte, s, l func(*[BlockSize]byte)) (ks [10][BlockSize]byte) {
 	var kr0 [BlockSize]byte
 	var kr1 [BlockSize]byte
 	var krt [BlockSize]byte
@@ -219,10 +234,20 @@ func NewCipher(key []byte) *Cipher {
 		copy(ks[2+2*i][:], kr0[:])
 		copy(ks[2+2*i+1][:], kr1[:])
 	}
-	return &Cipher{ks}
+	return
+}
+
+// Overwrite the round keys with zeros. Cipher is unusable afterwards:
+// Encrypt and Decrypt panic.
+func (c *Cipher) Zeroize() {
+	c.ks = [10][BlockSize]byte{}
+	c.zeroized = true
 }
 
 func (c *Cipher) Encrypt(dst, src []byte) {
+	if c.zeroized {
+		panic("gogost/gost3412128: cipher is zeroized")
+	}
 	blk := new([BlockSize]byte)
 	copy(blk[:], src)
 	for i := 0; i < 9; i++ {
@@ -235,6 +260,9 @@ func (c *Cipher) Encrypt(dst, src []byte) {
 }
 
 func (c *Cipher) Decrypt(dst, src []byte) {
+	if c.zeroized {
+		panic("gogost/gost3412128: cipher is zeroized")
+	}
 	blk := new([BlockSize]byte)
 	copy(blk[:], src)
 	for i := 9; i > 0; i-- {
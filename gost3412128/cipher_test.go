@@ -45,6 +45,20 @@ func TestCipherInterface(t *testing.T) {
 	var _ cipher.Block = NewCipher(make([]byte, KeySize))
 }
 
+func TestKeySize(t *testing.T) {
+	for _, l := range []int{0, KeySize - 1, KeySize + 1} {
+		func() {
+			defer func() {
+				if err, ok := recover().(KeySizeError); !ok || int(err) != l {
+					t.Fatal(l)
+				}
+			}()
+			NewCipher(make([]byte, l))
+		}()
+	}
+	NewCipher(make([]byte, KeySize))
+}
+
 func TestRandom(t *testing.T) {
 	data := make([]byte, BlockSize)
 	f := func(key [KeySize]byte, pt [BlockSize]byte) bool {
@@ -327,3 +341,32 @@ func TestVectorDecrypt(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestZeroize(t *testing.T) {
+	for _, c := range []interface {
+		cipher.Block
+		Zeroize()
+	}{NewCipher(key), NewCipherCT(key)} {
+		dst := make([]byte, BlockSize)
+		c.Encrypt(dst, pt[:])
+		if !bytes.Equal(dst, ct[:]) {
+			t.FailNow()
+		}
+		c.Zeroize()
+		for _, f := range []func(dst, src []byte){c.Encrypt, c.Decrypt} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Fatal("no panic after Zeroize")
+					}
+				}()
+				f(dst, pt[:])
+			}()
+		}
+	}
+	c := NewCipher(key)
+	c.Zeroize()
+	if c.ks != [10][BlockSize]byte{} {
+		t.Fatal("round keys are left")
+	}
+}
@@ -0,0 +1,133 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3412128
+
+// Kuznyechik implementation without secret dependent memory accesses
+// and branches. S-box lookups scan the whole table and GF(2^8)
+// multiplications are made with fixed number of masked shifts, so
+// cache-timing attacks are not applicable. It is about twenty times
+// slower than Cipher (see BenchmarkEncryptCT).
+type CipherCT struct {
+	ks       [10][BlockSize]byte
+	zeroized bool
+}
+
+// Create new constant-time cipher with KeySize-long key. Key schedule
+// is also computed in constant time. It panics with KeySizeError if
+// key has wrong length.
+func NewCipherCT(key []byte) *CipherCT {
+	if len(key) != KeySize {
+		panic(KeySizeError(len(key)))
+	}
+	return &CipherCT{ks: expandKey(key, sCT, lCT)}
+}
+
+func (c *CipherCT) BlockSize() int {
+	return BlockSize
+}
+
+// Overwrite the round keys with zeros. Cipher is unusable afterwards:
+// Encrypt and Decrypt panic.
+func (c *CipherCT) Zeroize() {
+	c.ks = [10][BlockSize]byte{}
+	c.zeroized = true
+}
+
+func (c *CipherCT) Encrypt(dst, src []byte) {
+	if c.zeroized {
+		panic("gogost/gost3412128: cipher is zeroized")
+	}
+	var blk [BlockSize]byte
+	copy(blk[:], src)
+	for i := 0; i < 9; i++ {
+		xor(blk[:], blk[:], c.ks[i][:])
+		sCT(&blk)
+		lCT(&blk)
+	}
+	xor(dst, blk[:], c.ks[9][:])
+}
+
+func (c *CipherCT) Decrypt(dst, src []byte) {
+	if c.zeroized {
+		panic("gogost/gost3412128: cipher is zeroized")
+	}
+	var blk [BlockSize]byte
+	copy(blk[:], src)
+	for i := 9; i > 0; i-- {
+		xor(blk[:], blk[:], c.ks[i][:])
+		lInvCT(&blk)
+		sInvCT(&blk)
+	}
+	xor(dst, blk[:], c.ks[0][:])
+}
+
+// Multiplication in GF(2^8) with x^8+x^7+x^6+x+1 polynomial, always
+// making eight iterations.
+func gfCT(a, b byte) (c byte) {
+	for i := 0; i < 8; i++ {
+		c ^= a & -(b & 1)
+		a = (a << 1) ^ (0xC3 & -(a >> 7))
+		b >>= 1
+	}
+	return
+}
+
+// Linear combination of the block with lc coefficients, that is R
+// transformation's feedback value.
+func lcCT(blk []byte) (t byte) {
+	for i := 0; i < BlockSize-1; i++ {
+		t ^= gfCT(blk[i], lc[i])
+	}
+	return
+}
+
+func lCT(blk *[BlockSize]byte) {
+	for n := 0; n < BlockSize; n++ {
+		t := blk[15] ^ lcCT(blk[:BlockSize-1])
+		copy(blk[1:], blk[:BlockSize-1])
+		blk[0] = t
+	}
+}
+
+func lInvCT(blk *[BlockSize]byte) {
+	for n := 0; n < BlockSize; n++ {
+		t := blk[0]
+		copy(blk[:], blk[1:])
+		blk[15] = t ^ lcCT(blk[:BlockSize-1])
+	}
+}
+
+// Look up x in the table, touching all of its entries.
+func lookupCT(table *[256]byte, x byte) (r byte) {
+	for i := 0; i < 256; i++ {
+		// 0xFF if i == x, 0x00 otherwise
+		eq := uint32(byte(i)^x) - 1
+		r |= table[i] & byte(eq>>24)
+	}
+	return
+}
+
+func sCT(blk *[BlockSize]byte) {
+	for n := 0; n < BlockSize; n++ {
+		blk[n] = lookupCT(&pi, blk[n])
+	}
+}
+
+func sInvCT(blk *[BlockSize]byte) {
+	for n := 0; n < BlockSize; n++ {
+		blk[n] = lookupCT(&piInv, blk[n])
+	}
+}
@@ -0,0 +1,96 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3412128
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"io"
+	"testing"
+	"testing/quick"
+)
+
+func TestCipherCTInterface(t *testing.T) {
+	var _ cipher.Block = NewCipherCT(make([]byte, KeySize))
+}
+
+func TestCipherCTVector(t *testing.T) {
+	c := NewCipherCT(key)
+	if c.ks != NewCipher(key).ks {
+		t.FailNow()
+	}
+	dst := make([]byte, BlockSize)
+	c.Encrypt(dst, pt[:])
+	if !bytes.Equal(dst, ct[:]) {
+		t.FailNow()
+	}
+	c.Decrypt(dst, ct[:])
+	if !bytes.Equal(dst, pt[:]) {
+		t.FailNow()
+	}
+}
+
+func TestCipherCTMatchesTable(t *testing.T) {
+	for a := 0; a < 256; a++ {
+		for b := 0; b < 256; b++ {
+			if gfCT(byte(a), byte(b)) != gfCache[a][b] {
+				t.Fatal(a, b)
+			}
+		}
+		if lookupCT(&pi, byte(a)) != pi[a] || lookupCT(&piInv, byte(a)) != piInv[a] {
+			t.Fatal(a)
+		}
+	}
+	expected := make([]byte, BlockSize)
+	got := make([]byte, BlockSize)
+	f := func(key [KeySize]byte, pt [BlockSize]byte) bool {
+		c := NewCipher(key[:])
+		cCT := NewCipherCT(key[:])
+		c.Encrypt(expected, pt[:])
+		cCT.Encrypt(got, pt[:])
+		if !bytes.Equal(got, expected) {
+			return false
+		}
+		cCT.Decrypt(got, got)
+		return bytes.Equal(got, pt[:])
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkEncryptCT(b *testing.B) {
+	key := make([]byte, KeySize)
+	io.ReadFull(rand.Reader, key)
+	c := NewCipherCT(key)
+	blk := make([]byte, BlockSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Encrypt(blk, blk)
+	}
+}
+
+func BenchmarkDecryptCT(b *testing.B) {
+	key := make([]byte, KeySize)
+	io.ReadFull(rand.Reader, key)
+	c := NewCipherCT(key)
+	blk := make([]byte, BlockSize)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Decrypt(blk, blk)
+	}
+}
@@ -0,0 +1,39 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3412128
+
+import (
+	"errors"
+
+	"github.com/hitchpock/gogost/v5/internal/keycheck"
+)
+
+// Error, NewCipherStrict returns, when key consists of the single
+// repeated byte, like all-zero or all-0xFF one.
+var ErrDegenerateKey = errors.New("gogost/gost3412128: degenerate key")
+
+// Same as NewCipher, but returns errors instead of panicking, also
+// rejecting degenerate keys with ErrDegenerateKey. Such keys usually
+// mean uninitialized buffer rather than the real key.
+func NewCipherStrict(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+	if keycheck.Degenerate(key) {
+		return nil, ErrDegenerateKey
+	}
+	return NewCipher(key), nil
+}
@@ -0,0 +1,49 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3412128
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestNewCipherStrict(t *testing.T) {
+	for _, b := range []byte{0x00, 0xFF, 0x42} {
+		if _, err := NewCipherStrict(bytes.Repeat([]byte{b}, KeySize)); !errors.Is(err, ErrDegenerateKey) {
+			t.Fatal("degenerate key accepted", b, err)
+		}
+	}
+	var ks KeySizeError
+	if _, err := NewCipherStrict(make([]byte, KeySize-1)); !errors.As(err, &ks) {
+		t.Fatal("short key accepted", err)
+	}
+	key := make([]byte, KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	c, err := NewCipherStrict(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]byte, BlockSize)
+	NewCipher(key).Encrypt(dst, dst)
+	got := make([]byte, BlockSize)
+	c.Encrypt(got, got)
+	if !bytes.Equal(got, dst) {
+		t.FailNow()
+	}
+}
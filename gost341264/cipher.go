@@ -17,6 +17,8 @@
 package gost341264
 
 import (
+	"strconv"
+
 	"github.com/hitchpock/gogost/v5/gost28147"
 )
 
@@ -25,28 +27,42 @@ const (
 	KeySize   = 32
 )
 
+// Error, NewCipher panics with, when key has wrong length.
+type KeySizeError int
+
+func (k KeySizeError) Error() string {
+	return "gogost/gost341264: invalid key size " + strconv.Itoa(int(k))
+}
+
 type Cipher struct {
 	c   *gost28147.Cipher
 	blk *[BlockSize]byte
 }
 
+// Create new cipher with KeySize-long key. It panics with KeySizeError
+// if key has wrong length.
 func NewCipher(key []byte) *Cipher {
 	if len(key) != KeySize {
-		panic("invalid key size")
-	}
-	keyCompatible := make([]byte, KeySize)
-	for i := 0; i < KeySize/4; i++ {
-		keyCompatible[i*4+0] = key[i*4+3]
-		keyCompatible[i*4+1] = key[i*4+2]
-		keyCompatible[i*4+2] = key[i*4+1]
-		keyCompatible[i*4+3] = key[i*4+0]
+		panic(KeySizeError(len(key)))
 	}
 	return &Cipher{
-		c:   gost28147.NewCipher(keyCompatible, &gost28147.SboxIdtc26gost28147paramZ),
+		c:   gost28147.NewCipher(keyCompatible(key), &gost28147.SboxIdtc26gost28147paramZ),
 		blk: new([BlockSize]byte),
 	}
 }
 
+// Convert the key to GOST 28147-89 byte order.
+func keyCompatible(key []byte) []byte {
+	compatible := make([]byte, KeySize)
+	for i := 0; i < KeySize/4; i++ {
+		compatible[i*4+0] = key[i*4+3]
+		compatible[i*4+1] = key[i*4+2]
+		compatible[i*4+2] = key[i*4+1]
+		compatible[i*4+3] = key[i*4+0]
+	}
+	return compatible
+}
+
 func (c *Cipher) BlockSize() int {
 	return BlockSize
 }
@@ -71,6 +87,13 @@ func (c *Cipher) Encrypt(dst, src []byte) {
 	dst[7] = c.blk[0]
 }
 
+// Overwrite the key schedule with zeros. Cipher is unusable afterwards:
+// Encrypt and Decrypt panic.
+func (c *Cipher) Zeroize() {
+	c.c.Zeroize()
+	*c.blk = [BlockSize]byte{}
+}
+
 func (c *Cipher) Decrypt(dst, src []byte) {
 	c.blk[0] = src[7]
 	c.blk[1] = src[6]
@@ -25,6 +25,20 @@ func TestCipherInterface(t *testing.T) {
 	var _ cipher.Block = NewCipher(make([]byte, KeySize))
 }
 
+func TestKeySize(t *testing.T) {
+	for _, l := range []int{0, KeySize - 1, KeySize + 1} {
+		func() {
+			defer func() {
+				if err, ok := recover().(KeySizeError); !ok || int(err) != l {
+					t.Fatal(l)
+				}
+			}()
+			NewCipher(make([]byte, l))
+		}()
+	}
+	NewCipher(make([]byte, KeySize))
+}
+
 func TestVector(t *testing.T) {
 	key := []byte{
 		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
@@ -45,3 +59,20 @@ func TestVector(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestZeroize(t *testing.T) {
+	c := NewCipher(make([]byte, KeySize))
+	dst := make([]byte, BlockSize)
+	c.Encrypt(dst, dst)
+	c.Zeroize()
+	for _, f := range []func(dst, src []byte){c.Encrypt, c.Decrypt} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("no panic after Zeroize")
+				}
+			}()
+			f(dst, dst)
+		}()
+	}
+}
@@ -0,0 +1,38 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost341264
+
+import "github.com/hitchpock/gogost/v5/gost28147"
+
+// Error, NewCipherStrict returns, when key consists of the single
+// repeated byte. It is the same error gost28147 returns.
+var ErrDegenerateKey = gost28147.ErrDegenerateKey
+
+// Same as NewCipher, but returns errors instead of panicking, also
+// rejecting degenerate keys with ErrDegenerateKey, using
+// gost28147.NewCipherStrict for the check.
+func NewCipherStrict(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, KeySizeError(len(key))
+	}
+	c, err := gost28147.NewCipherStrict(
+		keyCompatible(key), &gost28147.SboxIdtc26gost28147paramZ,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{c: c, blk: new([BlockSize]byte)}, nil
+}
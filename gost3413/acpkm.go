@@ -0,0 +1,164 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+)
+
+// Key size of ACPKM re-keying: both Magma and Kuznyechik have 256-bit keys.
+const ACPKMKeySize = 32
+
+// D constant of ACPKM: 0x80, 0x81, ..., 0x9F.
+var acpkmD = func() (d [ACPKMKeySize]byte) {
+	for i := range d {
+		d[i] = 0x80 + byte(i)
+	}
+	return
+}()
+
+// ACPKM re-keying (RFC 8645): the next section's key is encryption of
+// the D constant with the current section's key.
+func ACPKM(c cipher.Block) []byte {
+	blockSize := c.BlockSize()
+	key := make([]byte, ACPKMKeySize)
+	for i := 0; i < ACPKMKeySize; i += blockSize {
+		c.Encrypt(key[i:i+blockSize], acpkmD[i:i+blockSize])
+	}
+	return key
+}
+
+type ctrACPKM struct {
+	newCipher     func(key []byte) cipher.Block
+	c             cipher.Block
+	blockSize     int
+	sectionBlocks int
+	blocks        int
+	ctr           []byte
+	ks            []byte
+	buf           []byte
+	offset        int64
+	total         int64
+	tracer        Tracer
+}
+
+// CTR-ACPKM mode (RFC 8645): CTR with the key changed with ACPKM after
+// each sectionSize bytes, that must be a multiple of the block size.
+// newCipher creates the block cipher with the specified key, iv is the
+// half of the block. Returned stream is Traceable.
+func NewCTRACPKM(
+	newCipher func(key []byte) cipher.Block,
+	key, iv []byte,
+	sectionSize int,
+) (cipher.Stream, error) {
+	return newCTRACPKM(newCipher, key, iv, sectionSize)
+}
+
+func newCTRACPKM(
+	newCipher func(key []byte) cipher.Block,
+	key, iv []byte,
+	sectionSize int,
+) (*ctrACPKM, error) {
+	if len(key) != ACPKMKeySize {
+		return nil, fmt.Errorf("gogost/gost3413: len(key) != %d", ACPKMKeySize)
+	}
+	c := newCipher(key)
+	blockSize := c.BlockSize()
+	if blockSize != 8 && blockSize != 16 {
+		return nil, errors.New("gogost/gost3413: only {64|128} blocksizes allowed")
+	}
+	if len(iv) != blockSize/2 {
+		return nil, fmt.Errorf("gogost/gost3413: len(iv) != %d", blockSize/2)
+	}
+	if sectionSize <= 0 || sectionSize%blockSize != 0 {
+		return nil, fmt.Errorf("gogost/gost3413: section size %d is not a multiple of %d", sectionSize, blockSize)
+	}
+	s := ctrACPKM{
+		newCipher:     newCipher,
+		c:             c,
+		blockSize:     blockSize,
+		sectionBlocks: sectionSize / blockSize,
+		ctr:           make([]byte, blockSize),
+		buf:           make([]byte, blockSize),
+	}
+	copy(s.ctr, iv)
+	return &s, nil
+}
+
+func (s *ctrACPKM) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("gogost/gost3413: output smaller than input")
+	}
+	if s.tracer != nil && s.total == 0 && len(src) > 0 {
+		s.tracer.Trace(TraceEvent{
+			Kind: TraceStart,
+			IV:   append([]byte{}, s.ctr[:s.blockSize/2]...),
+		})
+	}
+	for len(src) > 0 {
+		if len(s.ks) == 0 {
+			if s.blocks == s.sectionBlocks {
+				s.c = s.newCipher(ACPKM(s.c))
+				s.blocks = 0
+				if s.tracer != nil {
+					s.tracer.Trace(TraceEvent{
+						Kind:   TraceKeyMeshing,
+						Offset: s.offset,
+						Blocks: s.total,
+					})
+				}
+			}
+			s.c.Encrypt(s.buf, s.ctr)
+			s.ks = s.buf
+			s.blocks++
+			s.total++
+			// Counter's half is incremented modulo 2^(n/2)
+			for i := s.blockSize - 1; i >= s.blockSize/2; i-- {
+				s.ctr[i]++
+				if s.ctr[i] != 0 {
+					break
+				}
+			}
+		}
+		n := len(src)
+		if n > len(s.ks) {
+			n = len(s.ks)
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ s.ks[i]
+		}
+		s.ks = s.ks[n:]
+		s.offset += int64(n)
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
+
+func (s *ctrACPKM) SetTracer(t Tracer) {
+	s.tracer = t
+}
+
+// Copy of the stream, continuing independently and untraced.
+func (s *ctrACPKM) clone() *ctrACPKM {
+	c := *s
+	c.tracer = nil
+	c.ctr = append([]byte{}, s.ctr...)
+	c.buf = append([]byte{}, s.buf...)
+	c.ks = c.buf[len(c.buf)-len(s.ks):]
+	return &c
+}
@@ -0,0 +1,292 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+var (
+	acpkmKey = []byte{
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+	}
+	acpkmPlaintext = []byte{
+		0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x00,
+		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a,
+		0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88,
+		0x99, 0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a, 0x00,
+		0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99,
+		0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a, 0x00, 0x11,
+		0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa,
+		0xbb, 0xcc, 0xee, 0xff, 0x0a, 0x00, 0x11, 0x22,
+		0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb,
+		0xcc, 0xee, 0xff, 0x0a, 0x00, 0x11, 0x22, 0x33,
+		0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc,
+		0xee, 0xff, 0x0a, 0x00, 0x11, 0x22, 0x33, 0x44,
+	}
+)
+
+func newKuznyechik(key []byte) cipher.Block {
+	return gost3412128.NewCipher(key)
+}
+
+func newMagma(key []byte) cipher.Block {
+	return gost341264.NewCipher(key)
+}
+
+// Test vectors from RFC 8645 appendix A
+func TestCTRACPKMKuznyechikVector(t *testing.T) {
+	s, err := NewCTRACPKM(
+		newKuznyechik, acpkmKey,
+		[]byte{0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xce, 0xf0},
+		32,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := make([]byte, len(acpkmPlaintext))
+	s.XORKeyStream(ct, acpkmPlaintext)
+	if !bytes.Equal(ct, []byte{
+		0xf1, 0x95, 0xd8, 0xbe, 0xc1, 0x0e, 0xd1, 0xdb,
+		0xd5, 0x7b, 0x5f, 0xa2, 0x40, 0xbd, 0xa1, 0xb8,
+		0x85, 0xee, 0xe7, 0x33, 0xf6, 0xa1, 0x3e, 0x5d,
+		0xf3, 0x3c, 0xe4, 0xb3, 0x3c, 0x45, 0xde, 0xe4,
+		0x4b, 0xce, 0xeb, 0x8f, 0x64, 0x6f, 0x4c, 0x55,
+		0x00, 0x17, 0x06, 0x27, 0x5e, 0x85, 0xe8, 0x00,
+		0x58, 0x7c, 0x4d, 0xf5, 0x68, 0xd0, 0x94, 0x39,
+		0x3e, 0x48, 0x34, 0xaf, 0xd0, 0x80, 0x50, 0x46,
+		0xcf, 0x30, 0xf5, 0x76, 0x86, 0xae, 0xec, 0xe1,
+		0x1c, 0xfc, 0x6c, 0x31, 0x6b, 0x8a, 0x89, 0x6e,
+		0xdf, 0xfd, 0x07, 0xec, 0x81, 0x36, 0x36, 0x46,
+		0x0c, 0x4f, 0x3b, 0x74, 0x34, 0x23, 0x16, 0x3e,
+		0x64, 0x09, 0xa9, 0xc2, 0x82, 0xfa, 0xc8, 0xd4,
+		0x69, 0xd2, 0x21, 0xe7, 0xfb, 0xd6, 0xde, 0x5d,
+	}) {
+		t.FailNow()
+	}
+}
+
+func TestCTRACPKMMagmaVector(t *testing.T) {
+	s, err := NewCTRACPKM(newMagma, acpkmKey, []byte{0x12, 0x34, 0x56, 0x78}, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct := make([]byte, 56)
+	s.XORKeyStream(ct, acpkmPlaintext[:56])
+	if !bytes.Equal(ct, []byte{
+		0x2a, 0xb8, 0x1d, 0xee, 0xeb, 0x1e, 0x4c, 0xab,
+		0x68, 0xe1, 0x04, 0xc4, 0xbd, 0x6b, 0x94, 0xea,
+		0xc7, 0x2c, 0x67, 0xaf, 0x6c, 0x2e, 0x5b, 0x6b,
+		0x0e, 0xaf, 0xb6, 0x17, 0x70, 0xf1, 0xb3, 0x2e,
+		0xa1, 0xae, 0x71, 0x14, 0x9e, 0xed, 0x13, 0x82,
+		0xab, 0xd4, 0x67, 0x18, 0x06, 0x72, 0xec, 0x6f,
+		0x84, 0xa2, 0xf1, 0x5b, 0x3f, 0xca, 0x72, 0xc1,
+	}) {
+		t.FailNow()
+	}
+}
+
+func TestACPKMMasterKuznyechikVector(t *testing.T) {
+	m, err := NewMACACPKM(newKuznyechik, acpkmKey, 32, 96, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	material := make([]byte, 80)
+	m.master.XORKeyStream(material, material)
+	if !bytes.Equal(material, []byte{
+		0x0c, 0xab, 0xf1, 0xf2, 0xef, 0xbc, 0x4a, 0xc1,
+		0x60, 0x48, 0xdf, 0x1a, 0x24, 0xc6, 0x05, 0xb2,
+		0xc0, 0xd1, 0x67, 0x3d, 0x75, 0x86, 0xa8, 0xec,
+		0x0d, 0xd4, 0x2c, 0x45, 0xa4, 0xf9, 0x5b, 0xae,
+		0x0f, 0x2e, 0x26, 0x17, 0xe4, 0x71, 0x48, 0x68,
+		0x0f, 0xc3, 0xe6, 0x17, 0x8d, 0xf2, 0xc1, 0x37,
+		0xc9, 0xdd, 0xa8, 0x9c, 0xff, 0xa4, 0x91, 0xfe,
+		0xad, 0xd9, 0xb3, 0xea, 0xb7, 0x03, 0xbb, 0x31,
+		0xbc, 0x7e, 0x92, 0x7f, 0x04, 0x94, 0x72, 0x9f,
+		0x51, 0xb4, 0x9d, 0x3d, 0xf9, 0xc9, 0x46, 0x08,
+	}) {
+		t.FailNow()
+	}
+}
+
+func TestMACACPKMKuznyechikVector(t *testing.T) {
+	m, err := NewMACACPKM(newKuznyechik, acpkmKey, 32, 96, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Write(acpkmPlaintext[:80])
+	if !bytes.Equal(m.Sum(nil), []byte{
+		0xfb, 0xb8, 0xdc, 0xee, 0x45, 0xbe, 0xa6, 0x7c,
+		0x35, 0xf5, 0x8c, 0x57, 0x00, 0x89, 0x8e, 0x5d,
+	}) {
+		t.FailNow()
+	}
+}
+
+func TestMACACPKMMagmaVector(t *testing.T) {
+	m, err := NewMACACPKM(newMagma, acpkmKey, 16, 80, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Write(acpkmPlaintext[:40])
+	if !bytes.Equal(m.Sum(nil), []byte{
+		0x34, 0x00, 0x8d, 0xad, 0x54, 0x96, 0xbb, 0x8e,
+	}) {
+		t.FailNow()
+	}
+}
+
+func TestCTRACPKMFirstSectionIsCTR(t *testing.T) {
+	iv := []byte{0x12, 0x34, 0x56, 0x78, 0x90, 0xab, 0xce, 0xf0}
+	s, err := NewCTRACPKM(newKuznyechik, acpkmKey, iv, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]byte, 48)
+	s.XORKeyStream(got, acpkmPlaintext[:48])
+	ctr := cipher.NewCTR(newKuznyechik(acpkmKey), append(iv, make([]byte, 8)...))
+	want := make([]byte, 48)
+	ctr.XORKeyStream(want, acpkmPlaintext[:48])
+	if !bytes.Equal(got[:32], want[:32]) || bytes.Equal(got[32:], want[32:]) {
+		t.FailNow()
+	}
+}
+
+func TestCTRACPKMSplit(t *testing.T) {
+	iv := []byte{0x12, 0x34, 0x56, 0x78}
+	f := func(data []byte, split uint8) bool {
+		if len(data) == 0 {
+			return true
+		}
+		s, err := NewCTRACPKM(newMagma, acpkmKey, iv, 16)
+		if err != nil {
+			return false
+		}
+		whole := make([]byte, len(data))
+		s.XORKeyStream(whole, data)
+		cut := int(split) % len(data)
+		s, _ = NewCTRACPKM(newMagma, acpkmKey, iv, 16)
+		parts := make([]byte, len(data))
+		s.XORKeyStream(parts[:cut], data[:cut])
+		s.XORKeyStream(parts[cut:], data[cut:])
+		return bytes.Equal(whole, parts)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMACACPKMSumIsIdempotent(t *testing.T) {
+	m, err := NewMACACPKM(newKuznyechik, acpkmKey, 32, 96, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sum on the section boundary, where keys of the next one are needed
+	for _, l := range []int{0, 16, 32, 48, 64, 80} {
+		m.Reset()
+		m.Write(acpkmPlaintext[:l])
+		tag1 := m.Sum(nil)
+		tag2 := m.Sum(nil)
+		m.Write(acpkmPlaintext[l:80])
+		tag3 := m.Sum(nil)
+		if !bytes.Equal(tag1, tag2) {
+			t.Fatal(l)
+		}
+		if l < 80 && bytes.Equal(tag1, tag3) {
+			t.Fatal(l)
+		}
+		if !bytes.Equal(tag3, []byte{
+			0xfb, 0xb8, 0xdc, 0xee, 0x45, 0xbe, 0xa6, 0x7c,
+			0x35, 0xf5, 0x8c, 0x57, 0x00, 0x89, 0x8e, 0x5d,
+		}) {
+			t.Fatal(l)
+		}
+	}
+}
+
+func TestCTRACPKMOMAC(t *testing.T) {
+	encKey := make([]byte, ACPKMKeySize)
+	macKey := make([]byte, ACPKMKeySize)
+	rand.Read(encKey)
+	rand.Read(macKey)
+	if _, err := NewCTRACPKMOMAC(newMagma, encKey, encKey, 16); err == nil {
+		t.Fatal("equal keys accepted")
+	}
+	if _, err := NewCTRACPKMOMAC(newMagma, encKey, macKey, 12); err == nil {
+		t.Fatal("invalid section size accepted")
+	}
+	for _, newCipher := range []func([]byte) cipher.Block{newMagma, newKuznyechik} {
+		aead, err := NewCTRACPKMOMAC(newCipher, encKey, macKey, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f := func(pt, ad []byte) bool {
+			nonce := make([]byte, aead.NonceSize())
+			rand.Read(nonce)
+			sealed := aead.Seal([]byte("prefix"), nonce, pt, ad)
+			if !bytes.HasPrefix(sealed, []byte("prefix")) {
+				return false
+			}
+			sealed = sealed[len("prefix"):]
+			if len(sealed) != len(pt)+aead.Overhead() {
+				return false
+			}
+			opened, err := aead.Open(nil, nonce, sealed, ad)
+			if err != nil || !bytes.Equal(opened, pt) {
+				return false
+			}
+			for _, i := range []int{0, len(sealed) - 1} {
+				sealed[i] ^= 1
+				if _, err = aead.Open(nil, nonce, sealed, ad); err != InvalidTag {
+					return false
+				}
+				sealed[i] ^= 1
+			}
+			nonce[0] ^= 1
+			if _, err = aead.Open(nil, nonce, sealed, ad); err != InvalidTag {
+				return false
+			}
+			nonce[0] ^= 1
+			if _, err = aead.Open(nil, nonce, sealed, append(ad, 0)); err != InvalidTag {
+				return false
+			}
+			return true
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestMACACPKMInvalidSize(t *testing.T) {
+	for _, size := range []int{-1, 0, gost3412128.BlockSize + 1} {
+		if _, err := NewMACACPKM(newKuznyechik, acpkmKey, 32, 96, size); err == nil {
+			t.Fatal("invalid size accepted", size)
+		}
+	}
+}
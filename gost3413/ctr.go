@@ -0,0 +1,84 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// CTR mode (GOST R 34.13-2015) stream, that can be re-seeded with
+// another IV without creating a new cipher, amortizing key schedule
+// cost across many short messages. It implements cipher.Stream.
+type ReusableCTR struct {
+	c         cipher.Block
+	blockSize int
+	ctr       []byte
+	ks        []byte
+	buf       []byte
+}
+
+// Create CTR stream over already keyed block cipher. Reset must be
+// called before the first use.
+func NewReusableCTR(c cipher.Block) *ReusableCTR {
+	blockSize := c.BlockSize()
+	return &ReusableCTR{
+		c:         c,
+		blockSize: blockSize,
+		ctr:       make([]byte, blockSize),
+		buf:       make([]byte, blockSize),
+	}
+}
+
+// Start the new keystream with iv, that is the half of the block.
+func (s *ReusableCTR) Reset(iv []byte) {
+	if len(iv) != s.blockSize/2 {
+		panic(fmt.Sprintf("gogost/gost3413: len(iv) != %d", s.blockSize/2))
+	}
+	copy(s.ctr, iv)
+	for i := len(iv); i < s.blockSize; i++ {
+		s.ctr[i] = 0
+	}
+	s.ks = nil
+}
+
+func (s *ReusableCTR) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("gogost/gost3413: output smaller than input")
+	}
+	for len(src) > 0 {
+		if len(s.ks) == 0 {
+			s.c.Encrypt(s.buf, s.ctr)
+			s.ks = s.buf
+			for i := s.blockSize - 1; i >= 0; i-- {
+				s.ctr[i]++
+				if s.ctr[i] != 0 {
+					break
+				}
+			}
+		}
+		n := len(src)
+		if n > len(s.ks) {
+			n = len(s.ks)
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ s.ks[i]
+		}
+		s.ks = s.ks[n:]
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
@@ -0,0 +1,50 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+func TestReusableCTR(t *testing.T) {
+	key := make([]byte, 32)
+	for _, c := range []cipher.Block{
+		gost341264.NewCipher(key),
+		gost3412128.NewCipher(key),
+	} {
+		s := NewReusableCTR(c)
+		f := func(iv [8]byte, data []byte) bool {
+			halfIV := iv[:c.BlockSize()/2]
+			s.Reset(halfIV)
+			got := make([]byte, len(data))
+			s.XORKeyStream(got, data)
+			fullIV := make([]byte, c.BlockSize())
+			copy(fullIV, halfIV)
+			expected := make([]byte, len(data))
+			cipher.NewCTR(c, fullIV).XORKeyStream(expected, data)
+			return bytes.Equal(got, expected)
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Error(err)
+		}
+	}
+}
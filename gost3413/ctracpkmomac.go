@@ -0,0 +1,126 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var InvalidTag = errors.New("gogost/gost3413: invalid authentication tag")
+
+type ctrACPKMOMAC struct {
+	newCipher   func(key []byte) cipher.Block
+	encKey      []byte
+	macKey      []byte
+	blockSize   int
+	sectionSize int
+}
+
+// Create encrypt-then-MAC AEAD with RFC 8645 modes: plaintext is
+// encrypted with CTR-ACPKM under encKey using the nonce (half of the
+// block) as IV, then OMAC-ACPKM under macKey with full block tag is
+// computed over nonce || BE64(bitlen(ad)) || BE64(bitlen(ciphertext))
+// || ad || ciphertext. Both modes use sectionSize sections, it is also
+// ACPKM-Master's section size. Keys must differ.
+func NewCTRACPKMOMAC(
+	newCipher func(key []byte) cipher.Block,
+	encKey, macKey []byte,
+	sectionSize int,
+) (cipher.AEAD, error) {
+	if len(encKey) != ACPKMKeySize || len(macKey) != ACPKMKeySize {
+		return nil, fmt.Errorf("gogost/gost3413: keys must be %d bytes long", ACPKMKeySize)
+	}
+	if subtle.ConstantTimeCompare(encKey, macKey) == 1 {
+		return nil, errors.New("gogost/gost3413: encryption and MAC keys are equal")
+	}
+	blockSize := newCipher(encKey).BlockSize()
+	if blockSize != 8 && blockSize != 16 {
+		return nil, errors.New("gogost/gost3413: only {64|128} blocksizes allowed")
+	}
+	if sectionSize <= 0 || sectionSize%blockSize != 0 {
+		return nil, fmt.Errorf("gogost/gost3413: section size %d is not a multiple of %d", sectionSize, blockSize)
+	}
+	return &ctrACPKMOMAC{
+		newCipher:   newCipher,
+		encKey:      append([]byte{}, encKey...),
+		macKey:      append([]byte{}, macKey...),
+		blockSize:   blockSize,
+		sectionSize: sectionSize,
+	}, nil
+}
+
+func (a *ctrACPKMOMAC) NonceSize() int {
+	return a.blockSize / 2
+}
+
+func (a *ctrACPKMOMAC) Overhead() int {
+	return a.blockSize
+}
+
+func (a *ctrACPKMOMAC) tag(nonce, ct, ad []byte) []byte {
+	m, err := NewMACACPKM(a.newCipher, a.macKey, a.sectionSize, a.sectionSize, a.blockSize)
+	if err != nil {
+		panic(err)
+	}
+	var lens [16]byte
+	binary.BigEndian.PutUint64(lens[:8], uint64(len(ad))*8)
+	binary.BigEndian.PutUint64(lens[8:], uint64(len(ct))*8)
+	m.Write(nonce)
+	m.Write(lens[:])
+	m.Write(ad)
+	m.Write(ct)
+	return m.Sum(nil)
+}
+
+func (a *ctrACPKMOMAC) xor(nonce, dst, src []byte) {
+	s, err := NewCTRACPKM(a.newCipher, a.encKey, nonce, a.sectionSize)
+	if err != nil {
+		panic(err)
+	}
+	s.XORKeyStream(dst, src)
+}
+
+func (a *ctrACPKMOMAC) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != a.NonceSize() {
+		panic("gogost/gost3413: incorrect nonce length")
+	}
+	ret, out := sliceForAppend(dst, len(plaintext)+a.blockSize)
+	ct := out[:len(plaintext)]
+	a.xor(nonce, ct, plaintext)
+	copy(out[len(plaintext):], a.tag(nonce, ct, additionalData))
+	return ret
+}
+
+func (a *ctrACPKMOMAC) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != a.NonceSize() {
+		panic("gogost/gost3413: incorrect nonce length")
+	}
+	if len(ciphertext) < a.blockSize {
+		return nil, errors.New("gogost/gost3413: ciphertext is too short")
+	}
+	ct := ciphertext[:len(ciphertext)-a.blockSize]
+	tag := ciphertext[len(ct):]
+	if subtle.ConstantTimeCompare(tag, a.tag(nonce, ct, additionalData)) != 1 {
+		return nil, InvalidTag
+	}
+	ret, out := sliceForAppend(dst, len(ct))
+	a.xor(nonce, out, ct)
+	return ret, nil
+}
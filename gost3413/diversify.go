@@ -0,0 +1,60 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+// Derive the key from the master one and the diversifier, as
+// smartcard-style key diversification schemes do. cipherName is either
+// "magma" or "kuznyechik". Full block MAC of the cipher under the
+// master key is computed over the one byte counter, starting from 1,
+// followed by the diversifier. Blocks for increasing counter values are
+// concatenated until the result is as long as the master key.
+func DiversifyKey(masterKey, diversifier []byte, cipherName string) ([]byte, error) {
+	var c cipher.Block
+	switch cipherName {
+	case "magma":
+		if len(masterKey) != gost341264.KeySize {
+			return nil, fmt.Errorf("gogost/gost3413.DiversifyKey: len(masterKey) != %d", gost341264.KeySize)
+		}
+		c = gost341264.NewCipher(masterKey)
+	case "kuznyechik":
+		if len(masterKey) != gost3412128.KeySize {
+			return nil, fmt.Errorf("gogost/gost3413.DiversifyKey: len(masterKey) != %d", gost3412128.KeySize)
+		}
+		c = gost3412128.NewCipher(masterKey)
+	default:
+		return nil, fmt.Errorf("gogost/gost3413.DiversifyKey: unknown cipher %q", cipherName)
+	}
+	m, err := NewMAC(c, c.BlockSize())
+	if err != nil {
+		return nil, fmt.Errorf("gogost/gost3413.DiversifyKey: %w", err)
+	}
+	key := make([]byte, 0, len(masterKey))
+	for i := byte(1); len(key) < len(masterKey); i++ {
+		m.Reset()
+		m.Write([]byte{i})
+		m.Write(diversifier)
+		key = m.Sum(key)
+	}
+	return key, nil
+}
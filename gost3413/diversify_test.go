@@ -0,0 +1,79 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+)
+
+func TestDiversifyKey(t *testing.T) {
+	f := func(master [32]byte, div1, div2 []byte) bool {
+		if bytes.Equal(div1, div2) {
+			return true
+		}
+		for _, name := range []string{"magma", "kuznyechik"} {
+			k1, err := DiversifyKey(master[:], div1, name)
+			if err != nil {
+				return false
+			}
+			if len(k1) != len(master) {
+				return false
+			}
+			again, err := DiversifyKey(master[:], div1, name)
+			if err != nil || !bytes.Equal(k1, again) {
+				return false
+			}
+			k2, err := DiversifyKey(master[:], div2, name)
+			if err != nil || bytes.Equal(k1, k2) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDiversifyKeyIsMAC(t *testing.T) {
+	master := make([]byte, 32)
+	div := []byte("card serial")
+	key, err := DiversifyKey(master, div, "kuznyechik")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMAC(gost3412128.NewCipher(master), gost3412128.BlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Write(append([]byte{1}, div...))
+	if !bytes.Equal(key[:gost3412128.BlockSize], m.Sum(nil)) {
+		t.FailNow()
+	}
+}
+
+func TestDiversifyKeyInvalid(t *testing.T) {
+	if _, err := DiversifyKey(make([]byte, 32), nil, "aes"); err == nil {
+		t.Fatal("unknown cipher accepted")
+	}
+	if _, err := DiversifyKey(make([]byte, 16), nil, "magma"); err == nil {
+		t.Fatal("short master key accepted")
+	}
+}
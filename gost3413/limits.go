@@ -0,0 +1,66 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"math"
+	"strings"
+)
+
+// Binary logarithm of the adversary's advantage MaxDataPerKey limits
+// keep the mode's security bound within.
+const maxDataAdvantageLog = -32
+
+// Get the maximal amount of data in bytes that is safe to process
+// under a single key of the block cipher in the specified mode: "ECB",
+// "CBC", "CFB", "OFB", "CTR", "MAC" or "MGM" (case insensitive).
+//
+// Like in RFC 8645, the limit is taken from the mode's security bound
+// c*s^2/2^n for s processed n-bit blocks, rather than the 2^(n/2)
+// birthday bound itself, where the attack already succeeds. s is
+// chosen so that the bound does not exceed 2^-32. Constant c is:
+//
+//   - 1/2 for ECB and CTR: PRP/PRF switching lemma;
+//   - 1 for OFB, whose keystream is the single chain of encryptions;
+//   - 2 for CBC and CFB (Bellare, Desai, Jokipii, Rogaway);
+//   - 4 for MAC (OMAC1, about 4s^2/2^n) and MGM (about 3s^2/2^n
+//     plus the forgery term, Akhmetzyanova et al.).
+//
+// For Magma it is 2^16 blocks (512 KiB) in OFB, for Kuznyechik 2^48
+// blocks (4 PiB). The key has to be changed beyond that, for example
+// with ACPKM re-keying. Zero is returned for unknown mode or block size.
+func MaxDataPerKey(mode string, block cipher.Block) int64 {
+	var c float64
+	switch strings.ToUpper(mode) {
+	case "ECB", "CTR":
+		c = 0.5
+	case "OFB":
+		c = 1
+	case "CBC", "CFB":
+		c = 2
+	case "MAC", "MGM":
+		c = 4
+	default:
+		return 0
+	}
+	blockSize := block.BlockSize()
+	if blockSize != 8 && blockSize != 16 {
+		return 0
+	}
+	blocks := math.Sqrt(math.Ldexp(1/c, blockSize*8+maxDataAdvantageLog))
+	return int64(blocks) * int64(blockSize)
+}
@@ -0,0 +1,56 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+func TestMaxDataPerKey(t *testing.T) {
+	magma := gost341264.NewCipher(make([]byte, gost341264.KeySize))
+	kuz := gost3412128.NewCipher(make([]byte, gost3412128.KeySize))
+	for _, v := range []struct {
+		mode  string
+		magma int64
+		kuz   int64
+	}{
+		{"ECB", 92681 * 8, 398065729532860 * 16},
+		{"CTR", 92681 * 8, 398065729532860 * 16},
+		{"OFB", 8 << 16, 16 << 48},
+		{"CBC", 46340 * 8, 199032864766430 * 16},
+		{"cfb", 46340 * 8, 199032864766430 * 16},
+		{"MAC", 8 << 15, 16 << 47},
+		{"mgm", 8 << 15, 16 << 47},
+	} {
+		if got := MaxDataPerKey(v.mode, magma); got != v.magma {
+			t.Fatal(v.mode, got)
+		}
+		if got := MaxDataPerKey(v.mode, kuz); got != v.kuz {
+			t.Fatal(v.mode, got)
+		}
+	}
+	if MaxDataPerKey("CTR", magma) <= MaxDataPerKey("OFB", magma) ||
+		MaxDataPerKey("OFB", magma) <= MaxDataPerKey("CBC", magma) ||
+		MaxDataPerKey("CBC", magma) <= MaxDataPerKey("MGM", magma) {
+		t.Fatal("modes limits do not differ")
+	}
+	if MaxDataPerKey("XTS", kuz) != 0 {
+		t.FailNow()
+	}
+}
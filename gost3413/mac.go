@@ -0,0 +1,139 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// GOST R 34.13-2015 MAC mode, also known as OMAC1/CMAC.
+// It implements hash.Hash interface.
+type MAC struct {
+	c         cipher.Block
+	size      int
+	blockSize int
+	k1        []byte
+	k2        []byte
+	prev      []byte
+	buf       []byte
+	tmp       []byte
+}
+
+// Create MAC with specified tag size in bytes (0<size<=BlockSize).
+// Only 64 and 128-bit block ciphers are supported: Magma with 4-byte
+// truncated tag is fine for lightweight profiles.
+func NewMAC(c cipher.Block, size int) (*MAC, error) {
+	blockSize := c.BlockSize()
+	var rb byte
+	switch blockSize {
+	case 8:
+		rb = 0x1B
+	case 16:
+		rb = 0x87
+	default:
+		return nil, errors.New("gogost/gost3413: only {64|128} blocksizes allowed")
+	}
+	if size <= 0 || size > blockSize {
+		return nil, fmt.Errorf("gogost/gost3413: invalid tag size (0<%d<=%d)", size, blockSize)
+	}
+	m := MAC{
+		c:         c,
+		size:      size,
+		blockSize: blockSize,
+		k1:        make([]byte, blockSize),
+		k2:        make([]byte, blockSize),
+		prev:      make([]byte, blockSize),
+		tmp:       make([]byte, blockSize),
+	}
+	c.Encrypt(m.k1, m.k1)
+	shl(m.k1, m.k1, rb)
+	shl(m.k2, m.k1, rb)
+	return &m, nil
+}
+
+// Shift src left by one bit, xor-ing with rb if the MSB was set.
+func shl(dst, src []byte, rb byte) {
+	msb := src[0] & 0x80
+	for i := 0; i < len(src)-1; i++ {
+		dst[i] = src[i]<<1 | src[i+1]>>7
+	}
+	dst[len(src)-1] = src[len(src)-1] << 1
+	if msb > 0 {
+		dst[len(src)-1] ^= rb
+	}
+}
+
+func (m *MAC) Reset() {
+	for i := 0; i < m.blockSize; i++ {
+		m.prev[i] = 0
+	}
+	m.buf = m.buf[:0]
+}
+
+func (m *MAC) BlockSize() int {
+	return m.blockSize
+}
+
+func (m *MAC) Size() int {
+	return m.size
+}
+
+func (m *MAC) Write(b []byte) (int, error) {
+	m.buf = append(m.buf, b...)
+	// Last full block must be kept for finalization with K1
+	for len(m.buf) > m.blockSize {
+		for i := 0; i < m.blockSize; i++ {
+			m.prev[i] ^= m.buf[i]
+		}
+		m.c.Encrypt(m.prev, m.prev)
+		m.buf = m.buf[m.blockSize:]
+	}
+	return len(b), nil
+}
+
+func (m *MAC) Sum(b []byte) []byte {
+	copy(m.tmp, m.buf)
+	k := m.k1
+	if len(m.buf) < m.blockSize {
+		m.tmp[len(m.buf)] = 0x80
+		for i := len(m.buf) + 1; i < m.blockSize; i++ {
+			m.tmp[i] = 0
+		}
+		k = m.k2
+	}
+	for i := 0; i < m.blockSize; i++ {
+		m.tmp[i] ^= m.prev[i] ^ k[i]
+	}
+	m.c.Encrypt(m.tmp, m.tmp)
+	return append(b, m.tmp[:m.size]...)
+}
+
+// Compute MAC of the message and compare it with the tag.
+// Comparison is done in constant time, so no timing information about
+// the number of matching tag bytes is leaked.
+func VerifyMAC(block cipher.Block, tagSize int, msg, tag []byte) (bool, error) {
+	m, err := NewMAC(block, tagSize)
+	if err != nil {
+		return false, err
+	}
+	if _, err = m.Write(msg); err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(m.Sum(nil), tag) == 1, nil
+}
@@ -0,0 +1,163 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+// Test vector from GOST R 34.13-2015 appendix
+func TestMACKuznechikVector(t *testing.T) {
+	c := gost3412128.NewCipher([]byte{
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10,
+		0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef,
+	})
+	pt := []byte{
+		0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x00,
+		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
+		0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+		0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a,
+		0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88,
+		0x99, 0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a, 0x00,
+		0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99,
+		0xaa, 0xbb, 0xcc, 0xee, 0xff, 0x0a, 0x00, 0x11,
+	}
+	tag := []byte{0x33, 0x6f, 0x4d, 0x29, 0x60, 0x59, 0xfb, 0xe3}
+	m, err := NewMAC(c, 8)
+	if err != nil {
+		t.FailNow()
+	}
+	m.Write(pt)
+	if !bytes.Equal(m.Sum(nil), tag) {
+		t.FailNow()
+	}
+	ok, err := VerifyMAC(c, 8, pt, tag)
+	if err != nil || !ok {
+		t.FailNow()
+	}
+	tag[7] ^= 1
+	ok, err = VerifyMAC(c, 8, pt, tag)
+	if err != nil || ok {
+		t.FailNow()
+	}
+	ok, err = VerifyMAC(c, 8, pt, tag[:4])
+	if err != nil || ok {
+		t.FailNow()
+	}
+}
+
+// Test vector from GOST R 34.13-2015 appendix
+func TestMACMagmaVector(t *testing.T) {
+	c := gost341264.NewCipher([]byte{
+		0xff, 0xee, 0xdd, 0xcc, 0xbb, 0xaa, 0x99, 0x88,
+		0x77, 0x66, 0x55, 0x44, 0x33, 0x22, 0x11, 0x00,
+		0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7,
+		0xf8, 0xf9, 0xfa, 0xfb, 0xfc, 0xfd, 0xfe, 0xff,
+	})
+	pt := []byte{
+		0x92, 0xde, 0xf0, 0x6b, 0x3c, 0x13, 0x0a, 0x59,
+		0xdb, 0x54, 0xc7, 0x04, 0xf8, 0x18, 0x9d, 0x20,
+		0x4a, 0x98, 0xfb, 0x2e, 0x67, 0xa8, 0x02, 0x4c,
+		0x89, 0x12, 0x40, 0x9b, 0x17, 0xb5, 0x7e, 0x41,
+	}
+	m, err := NewMAC(c, 4)
+	if err != nil {
+		t.FailNow()
+	}
+	if !bytes.Equal(m.k1, []byte{
+		0x5f, 0x45, 0x9b, 0x33, 0x42, 0x52, 0x14, 0x24,
+	}) {
+		t.FailNow()
+	}
+	if !bytes.Equal(m.k2, []byte{
+		0xbe, 0x8b, 0x36, 0x66, 0x84, 0xa4, 0x28, 0x48,
+	}) {
+		t.FailNow()
+	}
+	m.Write(pt)
+	if !bytes.Equal(m.Sum(nil), []byte{0x15, 0x4e, 0x72, 0x10}) {
+		t.FailNow()
+	}
+	m.Reset()
+	m.Write(pt[:13])
+	partial := m.Sum(nil)
+	ok, err := VerifyMAC(c, 4, pt[:13], partial)
+	if err != nil || !ok {
+		t.FailNow()
+	}
+}
+
+func TestMACSumIsIdempotent(t *testing.T) {
+	key := make([]byte, gost3412128.KeySize)
+	rand.Read(key)
+	c := gost3412128.NewCipher(key)
+	f := func(data []byte) bool {
+		m, _ := NewMAC(c, gost3412128.BlockSize)
+		m.Write(data)
+		tag1 := m.Sum(nil)
+		tag2 := m.Sum(nil)
+		m.Reset()
+		for _, b := range data {
+			m.Write([]byte{b})
+		}
+		return bytes.Equal(tag1, tag2) && bytes.Equal(tag1, m.Sum(nil))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMACInvalidSize(t *testing.T) {
+	c := gost3412128.NewCipher(make([]byte, gost3412128.KeySize))
+	if _, err := NewMAC(c, 0); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewMAC(c, -1); err == nil {
+		t.FailNow()
+	}
+	if _, err := NewMAC(c, gost3412128.BlockSize+1); err == nil {
+		t.FailNow()
+	}
+}
+
+func TestMACReset(t *testing.T) {
+	c := gost341264.NewCipher(make([]byte, gost341264.KeySize))
+	m, err := NewMAC(c, gost341264.BlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := func(data1, data2 []byte) bool {
+		m.Reset()
+		m.Write(data1)
+		m.Sum(nil)
+		m.Reset()
+		m.Write(data2)
+		fresh, _ := NewMAC(c, gost341264.BlockSize)
+		fresh.Write(data2)
+		return bytes.Equal(m.Sum(nil), fresh.Sum(nil))
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,192 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"crypto/cipher"
+	"errors"
+	"fmt"
+)
+
+// OMAC-ACPKM mode (RFC 8645). Each sectionSize bytes of the message
+// are processed with their own key, taken together with K1 subkey from
+// ACPKM-Master key material: CTR-ACPKM keystream with masterSectionSize
+// sections and all-ones IV. It implements hash.Hash and Traceable
+// interfaces.
+type MACACPKM struct {
+	newCipher         func(key []byte) cipher.Block
+	key               []byte
+	size              int
+	blockSize         int
+	rb                byte
+	sectionBlocks     int
+	masterSectionSize int
+
+	master *ctrACPKM
+	c      cipher.Block
+	k1     []byte
+	blocks int
+	total  int64
+	prev   []byte
+	buf    []byte
+	tmp    []byte
+	tracer Tracer
+}
+
+// Create OMAC-ACPKM with specified tag size in bytes (0<size<=BlockSize).
+// Both section sizes must be multiples of the block size.
+func NewMACACPKM(
+	newCipher func(key []byte) cipher.Block,
+	key []byte,
+	sectionSize, masterSectionSize, size int,
+) (*MACACPKM, error) {
+	if len(key) != ACPKMKeySize {
+		return nil, fmt.Errorf("gogost/gost3413: len(key) != %d", ACPKMKeySize)
+	}
+	blockSize := newCipher(key).BlockSize()
+	var rb byte
+	switch blockSize {
+	case 8:
+		rb = 0x1B
+	case 16:
+		rb = 0x87
+	default:
+		return nil, errors.New("gogost/gost3413: only {64|128} blocksizes allowed")
+	}
+	if size <= 0 || size > blockSize {
+		return nil, fmt.Errorf("gogost/gost3413: invalid tag size (0<%d<=%d)", size, blockSize)
+	}
+	if sectionSize <= 0 || sectionSize%blockSize != 0 {
+		return nil, fmt.Errorf("gogost/gost3413: section size %d is not a multiple of %d", sectionSize, blockSize)
+	}
+	m := MACACPKM{
+		newCipher:         newCipher,
+		key:               append([]byte{}, key...),
+		size:              size,
+		blockSize:         blockSize,
+		rb:                rb,
+		sectionBlocks:     sectionSize / blockSize,
+		masterSectionSize: masterSectionSize,
+		prev:              make([]byte, blockSize),
+		tmp:               make([]byte, blockSize),
+	}
+	if err := m.reset(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (m *MACACPKM) reset() (err error) {
+	iv := make([]byte, m.blockSize/2)
+	for i := range iv {
+		iv[i] = 0xFF
+	}
+	m.master, err = newCTRACPKM(m.newCipher, m.key, iv, m.masterSectionSize)
+	if err != nil {
+		return err
+	}
+	m.c = nil
+	m.k1 = nil
+	m.blocks = 0
+	m.total = 0
+	for i := 0; i < m.blockSize; i++ {
+		m.prev[i] = 0
+	}
+	m.buf = m.buf[:0]
+	return nil
+}
+
+// Get the next section's key and K1 from ACPKM-Master stream.
+func nextSectionKeys(master *ctrACPKM, blockSize int) (key, k1 []byte) {
+	material := make([]byte, ACPKMKeySize+blockSize)
+	master.XORKeyStream(material, material)
+	return material[:ACPKMKeySize], material[ACPKMKeySize:]
+}
+
+// Advance to the next block, switching the section keys if needed.
+func (m *MACACPKM) nextBlock() {
+	if m.c == nil || m.blocks == m.sectionBlocks {
+		if m.c != nil && m.tracer != nil {
+			m.tracer.Trace(TraceEvent{
+				Kind:   TraceKeyMeshing,
+				Offset: m.total * int64(m.blockSize),
+				Blocks: m.total,
+			})
+		}
+		key, k1 := nextSectionKeys(m.master, m.blockSize)
+		m.c = m.newCipher(key)
+		m.k1 = k1
+		m.blocks = 0
+	}
+	m.blocks++
+	m.total++
+}
+
+func (m *MACACPKM) SetTracer(t Tracer) {
+	m.tracer = t
+}
+
+func (m *MACACPKM) Reset() {
+	if err := m.reset(); err != nil {
+		panic(err)
+	}
+}
+
+func (m *MACACPKM) BlockSize() int {
+	return m.blockSize
+}
+
+func (m *MACACPKM) Size() int {
+	return m.size
+}
+
+func (m *MACACPKM) Write(b []byte) (int, error) {
+	m.buf = append(m.buf, b...)
+	// Last full block must be kept for finalization with K1
+	for len(m.buf) > m.blockSize {
+		m.nextBlock()
+		for i := 0; i < m.blockSize; i++ {
+			m.prev[i] ^= m.buf[i]
+		}
+		m.c.Encrypt(m.prev, m.prev)
+		m.buf = m.buf[m.blockSize:]
+	}
+	return len(b), nil
+}
+
+func (m *MACACPKM) Sum(b []byte) []byte {
+	c, k1 := m.c, m.k1
+	if c == nil || m.blocks == m.sectionBlocks {
+		// Sum must not change the state, so take the keys from the copy
+		key, nextK1 := nextSectionKeys(m.master.clone(), m.blockSize)
+		c, k1 = m.newCipher(key), nextK1
+	}
+	k := k1
+	copy(m.tmp, m.buf)
+	if len(m.buf) < m.blockSize {
+		m.tmp[len(m.buf)] = 0x80
+		for i := len(m.buf) + 1; i < m.blockSize; i++ {
+			m.tmp[i] = 0
+		}
+		k = make([]byte, m.blockSize)
+		shl(k, k1, m.rb)
+	}
+	for i := 0; i < m.blockSize; i++ {
+		m.tmp[i] ^= m.prev[i] ^ k[i]
+	}
+	c.Encrypt(m.tmp, m.tmp)
+	return append(b, m.tmp[:m.size]...)
+}
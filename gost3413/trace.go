@@ -0,0 +1,58 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+// Kind of the traced mode event.
+type TraceKind int
+
+const (
+	// Data processing is started with the IV.
+	TraceStart TraceKind = iota
+	// Section key is changed by key meshing.
+	TraceKeyMeshing
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceStart:
+		return "start"
+	case TraceKeyMeshing:
+		return "key meshing"
+	}
+	return "unknown"
+}
+
+// Event of the traced mode.
+type TraceEvent struct {
+	Kind TraceKind
+	// Offset in bytes of the processed data the event relates to.
+	Offset int64
+	// Number of data blocks processed before the event.
+	Blocks int64
+	// IV the mode is started with, set only for TraceStart.
+	IV []byte
+}
+
+// Receiver of the modes events, useful for debugging.
+type Tracer interface {
+	Trace(ev TraceEvent)
+}
+
+// Mode able to report its events. Tracer is nil by default, meaning no
+// tracing at all.
+type Traceable interface {
+	SetTracer(t Tracer)
+}
@@ -0,0 +1,94 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package gost3413
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+)
+
+type traceRecorder []TraceEvent
+
+func (r *traceRecorder) Trace(ev TraceEvent) {
+	*r = append(*r, ev)
+}
+
+func TestCTRACPKMTrace(t *testing.T) {
+	key := make([]byte, ACPKMKeySize)
+	iv := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	sectionSize := 2 * gost3412128.BlockSize
+	s, err := NewCTRACPKM(newKuznyechik, key, iv, sectionSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r traceRecorder
+	s.(Traceable).SetTracer(&r)
+	data := make([]byte, 100)
+	traced := make([]byte, len(data))
+	for i := 0; i < len(data); i += 7 {
+		end := i + 7
+		if end > len(data) {
+			end = len(data)
+		}
+		s.XORKeyStream(traced[i:end], data[i:end])
+	}
+	if len(r) != 4 {
+		t.Fatal("unexpected number of events", len(r))
+	}
+	if r[0].Kind != TraceStart || !bytes.Equal(r[0].IV, iv) {
+		t.Fatal("no start event")
+	}
+	for i, ev := range r[1:] {
+		offset := int64((i + 1) * sectionSize)
+		if ev.Kind != TraceKeyMeshing || ev.Offset != offset {
+			t.Fatal("meshing event", i, ev.Kind, ev.Offset)
+		}
+		if ev.Blocks != offset/gost3412128.BlockSize {
+			t.Fatal("meshing event blocks", i, ev.Blocks)
+		}
+	}
+	s, _ = NewCTRACPKM(newKuznyechik, key, iv, sectionSize)
+	untraced := make([]byte, len(data))
+	s.XORKeyStream(untraced, data)
+	if !bytes.Equal(traced, untraced) {
+		t.Fatal("tracing changed the keystream")
+	}
+}
+
+func TestMACACPKMTrace(t *testing.T) {
+	sectionSize := 2 * gost3412128.BlockSize
+	m, err := NewMACACPKM(
+		newKuznyechik, make([]byte, ACPKMKeySize),
+		sectionSize, 2*ACPKMKeySize, gost3412128.BlockSize,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r traceRecorder
+	m.SetTracer(&r)
+	m.Write(make([]byte, 3*sectionSize+gost3412128.BlockSize+1))
+	m.Sum(nil)
+	if len(r) != 3 {
+		t.Fatal("unexpected number of events", len(r))
+	}
+	for i, ev := range r {
+		if ev.Kind != TraceKeyMeshing || ev.Offset != int64((i+1)*sectionSize) {
+			t.Fatal("meshing event", i, ev.Kind, ev.Offset)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash"
 )
 
 const (
@@ -309,6 +310,7 @@ func New(size int) *Hash {
 	}
 	h := Hash{
 		size:    size,
+		buf:     make([]byte, 0, BlockSize),
 		hsh:     make([]byte, BlockSize),
 		chk:     make([]byte, BlockSize),
 		tmp:     make([]byte, BlockSize),
@@ -325,7 +327,7 @@ func New(size int) *Hash {
 
 func (h *Hash) Reset() {
 	h.n = 0
-	h.buf = nil
+	h.buf = h.buf[:0]
 	for i := 0; i < BlockSize; i++ {
 		h.chk[i] = 0
 		if h.size == 32 {
@@ -336,6 +338,18 @@ func (h *Hash) Reset() {
 	}
 }
 
+// Create independent deep copy of the hash state. It is useful for
+// hashing of the common prefix once and then finishing several copies
+// with different suffixes.
+func (h *Hash) Clone() hash.Hash {
+	c := New(h.size)
+	c.n = h.n
+	c.buf = append(make([]byte, 0, BlockSize), h.buf...)
+	copy(c.hsh, h.hsh)
+	copy(c.chk, h.chk)
+	return c
+}
+
 func (h *Hash) BlockSize() int {
 	return BlockSize
 }
@@ -372,6 +386,13 @@ func (h *Hash) Sum(in []byte) []byte {
 	return append(in, hsh...)
 }
 
+// Append the digest to dst without changing the running hash state,
+// exactly as Sum does. Neither of them allocates if dst has enough
+// capacity, so reusing dst[:0] in a loop is allocation-free.
+func (h *Hash) SumInto(dst []byte) []byte {
+	return h.Sum(dst)
+}
+
 func (h *Hash) add512bit(chk, data []byte) []byte {
 	var ss uint16
 	for i := 0; i < BlockSize; i++ {
@@ -395,6 +416,15 @@ func (h *Hash) g(n uint64, hsh, data []byte) []byte {
 	return blockXor(out, blockXor(out, h.e(l(out, h.ps(out)), data), hsh), data)
 }
 
+// Streebog's g_N compression function: hsh = g_N(hsh, m). All values
+// are 512-bit blocks in the hash's native (little-endian) byte order,
+// the same as Sum returns. See gost34112012512.Compress.
+func Compress(hsh, m, n *[BlockSize]byte) {
+	h := New(64)
+	out := blockXor(h.gBuf, hsh[:], n[:])
+	copy(hsh[:], blockXor(out, blockXor(out, h.e(l(out, h.ps(out)), m[:]), hsh[:]), m[:]))
+}
+
 func (h *Hash) e(k, msg []byte) []byte {
 	for i := 0; i < 12; i++ {
 		msg = l(h.eMsgBuf, h.ps(blockXor(h.eXorBuf, k, msg)))
@@ -466,6 +496,6 @@ func (h *Hash) UnmarshalBinary(data []byte) error {
 	idx += BlockSize
 	copy(h.chk, data[idx:])
 	idx += BlockSize
-	h.buf = data[idx:]
+	h.buf = append(make([]byte, 0, BlockSize), data[idx:]...)
 	return nil
 }
@@ -220,6 +220,38 @@ func TestRandom(t *testing.T) {
 	}
 }
 
+func TestClone(t *testing.T) {
+	f := func(prefix, suffix1, suffix2 []byte) bool {
+		for _, size := range []int{32, 64} {
+			h := New(size)
+			h.Write(prefix)
+			h1 := h.Clone()
+			h2 := h.Clone()
+			h1.Write(suffix1)
+			h2.Write(suffix2)
+			ref := New(size)
+			ref.Write(append(append([]byte{}, prefix...), suffix1...))
+			if !bytes.Equal(h1.Sum(nil), ref.Sum(nil)) {
+				return false
+			}
+			ref.Reset()
+			ref.Write(append(append([]byte{}, prefix...), suffix2...))
+			if !bytes.Equal(h2.Sum(nil), ref.Sum(nil)) {
+				return false
+			}
+			ref.Reset()
+			ref.Write(prefix)
+			if !bytes.Equal(h.Sum(nil), ref.Sum(nil)) {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
 func BenchmarkHash(b *testing.B) {
 	h := New(64)
 	src := make([]byte, BlockSize+1)
@@ -230,3 +262,66 @@ func BenchmarkHash(b *testing.B) {
 		h.Sum(nil)
 	}
 }
+
+func TestSumInto(t *testing.T) {
+	h := New(32)
+	msg := make([]byte, 100)
+	rand.Read(msg)
+	h.Write(msg[:50])
+	dst := make([]byte, 0, 64)
+	dst = h.SumInto(dst)
+	if !bytes.Equal(dst, h.Sum(nil)) {
+		t.FailNow()
+	}
+	h.Write(msg[50:])
+	full := New(32)
+	full.Write(msg)
+	if !bytes.Equal(h.SumInto(dst[:0]), full.Sum(nil)) {
+		t.FailNow()
+	}
+	if allocs := testing.AllocsPerRun(100, func() {
+		h.Reset()
+		h.Write(msg[:32])
+		dst = h.SumInto(dst[:0])
+	}); allocs != 0 {
+		t.Fatal(allocs)
+	}
+}
+
+func BenchmarkSumInto(b *testing.B) {
+	h := New(32)
+	src := make([]byte, 32)
+	rand.Read(src)
+	dst := make([]byte, 0, 32)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Reset()
+		h.Write(src)
+		dst = h.SumInto(dst[:0])
+	}
+}
+
+func TestCompress(t *testing.T) {
+	// Reproduce 512-bit hash of M1 from the standard's example step
+	// by step: padded message, its length and checksum compressions
+	var hsh, m, n [BlockSize]byte
+	for i := 0; i < 63; i++ {
+		m[i] = 0x30 + byte(i%10)
+	}
+	m[63] = 0x01
+	h := New(64)
+	h.Write(m[:63])
+	Compress(&hsh, &m, &n)
+	n[0] = 0xF8
+	n[1] = 0x01
+	var zero [BlockSize]byte
+	Compress(&hsh, &n, &zero)
+	Compress(&hsh, &m, &zero)
+	if !bytes.Equal(hsh[:], h.Sum(nil)) {
+		t.FailNow()
+	}
+	if !bytes.Equal(hsh[:8], []byte{0x1b, 0x54, 0xd0, 0x1a, 0x4a, 0xf5, 0xb9, 0xd5}) {
+		t.FailNow()
+	}
+}
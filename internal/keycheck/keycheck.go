@@ -0,0 +1,29 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Sanity checks of symmetric keys, shared between block ciphers.
+package keycheck
+
+// Does key consist of the single repeated byte, like all-zero or
+// all-0xFF one. Such keys usually mean uninitialized buffer rather
+// than the real key.
+func Degenerate(key []byte) bool {
+	for _, b := range key[1:] {
+		if b != key[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Merkle trees with GOST R 34.11-2012 Streebog node hash.
+//
+// Tree is built like in RFC 6962, with Streebog instead of SHA-256.
+// Leaves are hashed as H(0x00 || leaf) and internal nodes as
+// H(0x01 || left || right), so leaf can not be confused with the node.
+// Last node of the level with odd number of nodes is carried up to the
+// next level as is, without being paired with its duplicate, so a, b, c
+// and a, b, c, c leaves lists have different roots.
+package merkle
+
+import (
+	"crypto/hmac"
+	"hash"
+
+	"github.com/hitchpock/gogost/v5/internal/gost34112012"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// Create Streebog of bits digest size. It panics if bits is neither
+// 256, nor 512.
+func newHash(bits int) hash.Hash {
+	if bits != 256 && bits != 512 {
+		panic("gogost/merkle: bits must be either 256 or 512")
+	}
+	return gost34112012.New(bits / 8)
+}
+
+func leafHash(h hash.Hash, leaf []byte) []byte {
+	h.Reset()
+	h.Write([]byte{leafPrefix})
+	h.Write(leaf)
+	return h.Sum(nil)
+}
+
+func nodeHash(h hash.Hash, left, right []byte) []byte {
+	h.Reset()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// Compute the root of Merkle tree over the leaves with Streebog of
+// bits (256 or 512) digest size. Root of the empty tree is the hash of
+// the empty string.
+func MerkleRoot(leaves [][]byte, bits int) []byte {
+	h := newHash(bits)
+	if len(leaves) == 0 {
+		return h.Sum(nil)
+	}
+	level := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		level = append(level, leafHash(h, leaf))
+	}
+	for len(level) > 1 {
+		half := len(level) / 2
+		for i := 0; i < half; i++ {
+			level[i] = nodeHash(h, level[2*i], level[2*i+1])
+		}
+		if len(level)%2 == 1 {
+			level[half] = level[len(level)-1]
+			half++
+		}
+		level = level[:half]
+	}
+	return level[0]
+}
+
+// Verify that the leaf with the index is included into the tree of n
+// leaves with the root, recomputing the root from the sibling path, as
+// RFC 9162 2.1.3.2 does. proof holds the siblings from the leaf's level
+// up to the root's children, skipping the levels the node is carried
+// up on. Index out of [0, n) range is rejected. bits is Streebog's
+// digest size, like in MerkleRoot.
+func VerifyMerkleProof(leaf []byte, proof [][]byte, index, n int, root []byte, bits int) bool {
+	h := newHash(bits)
+	if index < 0 || index >= n {
+		return false
+	}
+	node := leafHash(h, leaf)
+	fn, sn := index, n-1
+	for _, sibling := range proof {
+		if len(sibling) != h.Size() || sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			node = nodeHash(h, sibling, node)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			node = nodeHash(h, node, sibling)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return false
+	}
+	return hmac.Equal(node, root)
+}
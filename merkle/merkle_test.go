@@ -0,0 +1,165 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package merkle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hitchpock/gogost/v5/gost34112012256"
+	"github.com/hitchpock/gogost/v5/gost34112012512"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	a, b, c, d := []byte("a"), []byte("b"), []byte("c"), []byte("d")
+	h := gost34112012256.New()
+	la, lb := leafHash(h, a), leafHash(h, b)
+	lc, ld := leafHash(h, c), leafHash(h, d)
+	if !bytes.Equal(MerkleRoot([][]byte{a}, 256), la) {
+		t.Fatal("1 leaf")
+	}
+	ab := nodeHash(h, la, lb)
+	if !bytes.Equal(MerkleRoot([][]byte{a, b}, 256), ab) {
+		t.Fatal("2 leaves")
+	}
+	if !bytes.Equal(MerkleRoot([][]byte{a, b, c}, 256), nodeHash(h, ab, lc)) {
+		t.Fatal("3 leaves")
+	}
+	if bytes.Equal(MerkleRoot([][]byte{a, b, c}, 256), MerkleRoot([][]byte{a, b, c, c}, 256)) {
+		t.Fatal("duplicated last leaf gives the same root")
+	}
+	if !bytes.Equal(MerkleRoot([][]byte{a, b, c, d}, 256), nodeHash(h, ab, nodeHash(h, lc, ld))) {
+		t.Fatal("4 leaves")
+	}
+	if bytes.Equal(MerkleRoot([][]byte{a, b}, 256), MerkleRoot([][]byte{b, a}, 256)) {
+		t.Fatal("order is ignored")
+	}
+}
+
+func TestMerkleRootLeafIsNotNode(t *testing.T) {
+	h := gost34112012512.New()
+	la, lb := leafHash(h, []byte("a")), leafHash(h, []byte("b"))
+	root := MerkleRoot([][]byte{[]byte("a"), []byte("b")}, 512)
+	if len(root) != gost34112012512.Size {
+		t.Fatal("wrong size")
+	}
+	if bytes.Equal(MerkleRoot([][]byte{append(la, lb...)}, 512), root) {
+		t.Fatal("node is forged with the leaf")
+	}
+}
+
+func TestMerkleRootVector(t *testing.T) {
+	// RFC 6962 tree over a, b, c, d, e, computed with plain Streebog:
+	// e is carried up to the root level without hashing
+	sum := func(data ...[]byte) []byte {
+		h := gost34112012256.New()
+		for _, d := range data {
+			h.Write(d)
+		}
+		return h.Sum(nil)
+	}
+	leaf, node := []byte{0x00}, []byte{0x01}
+	la, lb, lc := sum(leaf, []byte("a")), sum(leaf, []byte("b")), sum(leaf, []byte("c"))
+	ld, le := sum(leaf, []byte("d")), sum(leaf, []byte("e"))
+	abcd := sum(node, sum(node, la, lb), sum(node, lc, ld))
+	expected := sum(node, abcd, le)
+	leaves := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+	if !bytes.Equal(MerkleRoot(leaves, 256), expected) {
+		t.Fatalf("%x", MerkleRoot(leaves, 256))
+	}
+	if !bytes.Equal(MerkleRoot(nil, 256), sum()) {
+		t.Fatal("empty tree")
+	}
+}
+
+// Build inclusion proof of leaves[index] the way MerkleRoot builds
+// the tree: carried up node has no sibling on its level.
+func merkleProof(leaves [][]byte, index, bits int) (proof [][]byte) {
+	h := newHash(bits)
+	level := make([][]byte, 0, len(leaves))
+	for _, leaf := range leaves {
+		level = append(level, leafHash(h, leaf))
+	}
+	for len(level) > 1 {
+		if index^1 < len(level) {
+			proof = append(proof, level[index^1])
+		}
+		half := len(level) / 2
+		for i := 0; i < half; i++ {
+			level[i] = nodeHash(h, level[2*i], level[2*i+1])
+		}
+		if len(level)%2 == 1 {
+			level[half] = level[len(level)-1]
+			half++
+		}
+		level = level[:half]
+		index >>= 1
+	}
+	return proof
+}
+
+func TestVerifyMerkleProof(t *testing.T) {
+	for _, bits := range []int{256, 512} {
+		for n := 1; n <= 9; n++ {
+			leaves := make([][]byte, n)
+			for i := range leaves {
+				leaves[i] = []byte{byte(i)}
+			}
+			root := MerkleRoot(leaves, bits)
+			for i, leaf := range leaves {
+				proof := merkleProof(leaves, i, bits)
+				if !VerifyMerkleProof(leaf, proof, i, n, root, bits) {
+					t.Fatal("valid proof is rejected", bits, n, i)
+				}
+				if VerifyMerkleProof([]byte("other"), proof, i, n, root, bits) {
+					t.Fatal("wrong leaf is accepted", bits, n, i)
+				}
+				if VerifyMerkleProof(leaf, proof, n, n, root, bits) {
+					t.Fatal("index out of tree is accepted", bits, n, i)
+				}
+				if len(proof) == 0 {
+					continue
+				}
+				wrong := append([][]byte{}, proof...)
+				wrong[0] = make([]byte, len(proof[0]))
+				if VerifyMerkleProof(leaf, wrong, i, n, root, bits) {
+					t.Fatal("wrong sibling is accepted", bits, n, i)
+				}
+				if VerifyMerkleProof(leaf, proof, i^1, n, root, bits) {
+					t.Fatal("wrong index is accepted", bits, n, i)
+				}
+				if VerifyMerkleProof(leaf, proof[:len(proof)-1], i, n, root, bits) {
+					t.Fatal("short proof is accepted", bits, n, i)
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyMerkleProofNonexistentLeaf(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	h := gost34112012256.New()
+	lc, ab := leafHash(h, c), nodeHash(h, leafHash(h, a), leafHash(h, b))
+	root := MerkleRoot([][]byte{a, b, c}, 256)
+	if !VerifyMerkleProof(c, [][]byte{ab}, 2, 3, root, 256) {
+		t.Fatal("valid proof is rejected")
+	}
+	for index := 0; index < 8; index++ {
+		if VerifyMerkleProof(c, [][]byte{lc, ab}, index, 3, root, 256) {
+			t.Fatal("nonexistent leaf is accepted", index)
+		}
+	}
+}
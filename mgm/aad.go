@@ -0,0 +1,57 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+// AADSealer accumulates additional authenticated data in pieces before
+// sealing or opening. MGM's authentication keystream depends on the
+// nonce, which is known only at Seal/Open time, so pieces are
+// collected internally and authenticated exactly as if they were
+// passed as a single blob to MGM.Seal/MGM.Open.
+type AADSealer struct {
+	mgm *MGM
+	ad  []byte
+}
+
+// Create new AAD sealer on top of mgm.
+func (mgm *MGM) NewAADSealer() *AADSealer {
+	return &AADSealer{mgm: mgm}
+}
+
+// Append another piece of additional authenticated data.
+func (s *AADSealer) AddAAD(data []byte) {
+	s.ad = append(s.ad, data...)
+}
+
+// Forget all collected additional authenticated data.
+func (s *AADSealer) Reset() {
+	s.ad = s.ad[:0]
+}
+
+// Encrypt and authenticate plaintext with collected AAD. The result is
+// ciphertext with appended tag. Collected AAD is reset afterwards.
+func (s *AADSealer) Seal(nonce, plaintext []byte) []byte {
+	out := s.mgm.Seal(nil, nonce, plaintext, s.ad)
+	s.Reset()
+	return out
+}
+
+// Authenticate and decrypt ciphertext with collected AAD. Collected AAD
+// is reset afterwards.
+func (s *AADSealer) Open(nonce, ciphertext []byte) ([]byte, error) {
+	out, err := s.mgm.Open(nil, nonce, ciphertext, s.ad)
+	s.Reset()
+	return out, err
+}
@@ -0,0 +1,84 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost341264"
+)
+
+func TestAADSealerPiecewise(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	for _, aead := range []*MGM{
+		mustMGM(t, gost3412128.NewCipher(key), 16),
+		mustMGM(t, gost341264.NewCipher(key), 8),
+	} {
+		nonce := make([]byte, aead.NonceSize())
+		sealer := aead.NewAADSealer()
+		f := func(pieces [][]byte, pt []byte) bool {
+			if _, err := rand.Read(nonce[1:]); err != nil {
+				panic(err)
+			}
+			var blob []byte
+			for _, piece := range pieces {
+				blob = append(blob, piece...)
+			}
+			if len(blob) == 0 && len(pt) == 0 {
+				return true
+			}
+			for _, piece := range pieces {
+				sealer.AddAAD(piece)
+			}
+			ct := sealer.Seal(nonce, pt)
+			if !bytes.Equal(ct, aead.Seal(nil, nonce, pt, blob)) {
+				return false
+			}
+			for _, piece := range pieces {
+				sealer.AddAAD(piece)
+			}
+			got, err := sealer.Open(nonce, ct)
+			if err != nil || !bytes.Equal(got, pt) {
+				return false
+			}
+			if len(blob) > 0 {
+				if _, err = sealer.Open(nonce, ct); err == nil {
+					return false
+				}
+			}
+			return true
+		}
+		if err := quick.Check(f, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func mustMGM(t *testing.T, c cipher.Block, tagSize int) *MGM {
+	aead, err := NewMGM(c, tagSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aead.(*MGM)
+}
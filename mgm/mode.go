@@ -43,7 +43,24 @@ type MGM struct {
 	mul       Mul
 }
 
+// Minimal tag size NewMGM accepts. Forgery succeeds with 2^(-8*tagSize)
+// probability per attempt, so shorter tags are too weak for general
+// use. Full tag equals to the cipher's block size.
+const MinTagSize = 8
+
+// Create MGM AEAD with tagSize within [MinTagSize, cipher.BlockSize()].
 func NewMGM(cipher cipher.Block, tagSize int) (cipher.AEAD, error) {
+	if tagSize < MinTagSize {
+		return nil, fmt.Errorf("gogost/mgm: too short tag size (%d<%d)", tagSize, MinTagSize)
+	}
+	return NewMGMUnsafe(cipher, tagSize)
+}
+
+// The same as NewMGM, but allows tags down to 4 bytes, that the
+// standard permits. Use only when the protocol limits the number of
+// forgery attempts, as each one succeeds with 2^(-8*tagSize)
+// probability.
+func NewMGMUnsafe(cipher cipher.Block, tagSize int) (cipher.AEAD, error) {
 	blockSize := cipher.BlockSize()
 	if !(blockSize == 8 || blockSize == 16) {
 		return nil, errors.New("gogost/mgm: only {64|128} blocksizes allowed")
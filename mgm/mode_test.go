@@ -96,7 +96,7 @@ func TestSymmetric(t *testing.T) {
 				return true
 			}
 			tagSize = 4 + tagSize%uint8(blockSize-4)
-			aead, err := NewMGM(c, int(tagSize))
+			aead, err := NewMGMUnsafe(c, int(tagSize))
 			if err != nil {
 				return false
 			}
@@ -147,6 +147,27 @@ func TestSymmetric(t *testing.T) {
 	)
 }
 
+func TestTagSize(t *testing.T) {
+	key := make([]byte, gost3412128.KeySize)
+	c := gost3412128.NewCipher(key)
+	for _, tagSize := range []int{MinTagSize, 12, gost3412128.BlockSize} {
+		if _, err := NewMGM(c, tagSize); err != nil {
+			t.Fatal(tagSize, err)
+		}
+	}
+	for _, tagSize := range []int{0, 4, MinTagSize - 1, gost3412128.BlockSize + 1} {
+		if _, err := NewMGM(c, tagSize); err == nil {
+			t.Fatal(tagSize)
+		}
+	}
+	if _, err := NewMGMUnsafe(c, 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMGMUnsafe(c, 3); err == nil {
+		t.FailNow()
+	}
+}
+
 func BenchmarkMGM64(b *testing.B) {
 	key := make([]byte, gost341264.KeySize)
 	if _, err := io.ReadFull(rand.Reader, key); err != nil {
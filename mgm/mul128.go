@@ -40,7 +40,7 @@ func gf128half(n int, t, x0, x1, z0, z1 uint64) (uint64, uint64, uint64, uint64,
 	return t, x0, x1, z0, z1
 }
 
-func (mul *mul128) Mul(x, y []byte) []byte {
+func (mul *mul128) mulGeneric(x, y []byte) []byte {
 	x1 := binary.BigEndian.Uint64(x[:8])
 	x0 := binary.BigEndian.Uint64(x[8:])
 	y1 := binary.BigEndian.Uint64(y[:8])
@@ -0,0 +1,35 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build amd64 && !purego
+// +build amd64,!purego
+
+package mgm
+
+import "golang.org/x/sys/cpu"
+
+// Is carry-less multiplication instruction available.
+var useCLMUL = cpu.X86.HasPCLMULQDQ && cpu.X86.HasSSSE3
+
+//go:noescape
+func mul128CLMUL(z, x, y *[16]byte)
+
+func (mul *mul128) Mul(x, y []byte) []byte {
+	if useCLMUL {
+		mul128CLMUL(&mul.buf, (*[16]byte)(x), (*[16]byte)(y))
+		return mul.buf[:]
+	}
+	return mul.mulGeneric(x, y)
+}
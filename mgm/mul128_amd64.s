@@ -0,0 +1,74 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build amd64 && !purego
+// +build amd64,!purego
+
+#include "textflag.h"
+
+// Reverse bytes order for big-endian <-> little-endian conversion
+DATA bswapMask<>+0x00(SB)/8, $0x08090a0b0c0d0e0f
+DATA bswapMask<>+0x08(SB)/8, $0x0001020304050607
+GLOBL bswapMask<>(SB), (NOPTR+RODATA), $16
+
+// x^128 = x^7 + x^2 + x + 1
+DATA poly<>+0x00(SB)/8, $0x0000000000000087
+DATA poly<>+0x08(SB)/8, $0x0000000000000000
+GLOBL poly<>(SB), (NOPTR+RODATA), $16
+
+// func mul128CLMUL(z, x, y *[16]byte)
+TEXT ·mul128CLMUL(SB), NOSPLIT, $0-24
+	MOVQ z+0(FP), DI
+	MOVQ x+8(FP), SI
+	MOVQ y+16(FP), DX
+
+	MOVOU bswapMask<>(SB), X7
+	MOVOU (SI), X0
+	MOVOU (DX), X1
+	PSHUFB X7, X0 // x1:x0
+	PSHUFB X7, X1 // y1:y0
+
+	// 256-bit carry-less product H:L
+	MOVOU X0, X2
+	PCLMULQDQ $0x00, X1, X2 // L = x0*y0
+	MOVOU X0, X3
+	PCLMULQDQ $0x11, X1, X3 // H = x1*y1
+	MOVOU X0, X4
+	PCLMULQDQ $0x01, X1, X4 // x1*y0
+	MOVOU X0, X5
+	PCLMULQDQ $0x10, X1, X5 // x0*y1
+	PXOR X5, X4
+	MOVOU X4, X5
+	PSLLDQ $8, X5
+	PSRLDQ $8, X4
+	PXOR X5, X2
+	PXOR X4, X3
+
+	// Reduction: H*x^128 = h1*0x87*x^64 + h0*0x87, where
+	// h1*0x87 = T1*x^64 + T0 and T1*x^128 is folded into h0
+	MOVOU poly<>(SB), X6
+	MOVOU X3, X4
+	PCLMULQDQ $0x01, X6, X4 // T = h1*0x87
+	MOVOU X4, X5
+	PSLLDQ $8, X5
+	PXOR X5, X2 // L ^= T0*x^64
+	PSRLDQ $8, X4
+	PXOR X4, X3             // h0 ^= T1
+	PCLMULQDQ $0x00, X6, X3 // (h0^T1)*0x87
+	PXOR X3, X2
+
+	PSHUFB X7, X2
+	MOVOU X2, (DI)
+	RET
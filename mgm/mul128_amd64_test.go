@@ -0,0 +1,84 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+//go:build amd64 && !purego
+// +build amd64,!purego
+
+package mgm
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+)
+
+func TestMul128CLMULCross(t *testing.T) {
+	if !useCLMUL {
+		t.Skip("no PCLMULQDQ instruction")
+	}
+	mulCLMUL := newMul128()
+	mulGeneric := newMul128()
+	f := func(x, y [16]byte) bool {
+		return bytes.Equal(mulCLMUL.Mul(x[:], y[:]), mulGeneric.mulGeneric(x[:], y[:]))
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+	ones := bytes.Repeat([]byte{0xFF}, 16)
+	if !bytes.Equal(mulCLMUL.Mul(ones, ones), mulGeneric.mulGeneric(ones, ones)) {
+		t.FailNow()
+	}
+}
+
+func TestMGMCLMULCross(t *testing.T) {
+	if !useCLMUL {
+		t.Skip("no PCLMULQDQ instruction")
+	}
+	key := make([]byte, gost3412128.KeySize)
+	rand.Read(key)
+	nonce := make([]byte, gost3412128.BlockSize)
+	aead, err := NewMGM(gost3412128.NewCipher(key), gost3412128.BlockSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := func(pt, ad []byte) bool {
+		if len(pt) == 0 && len(ad) == 0 {
+			return true
+		}
+		sealedCLMUL := aead.Seal(nil, nonce, pt, ad)
+		useCLMUL = false
+		sealedGeneric := aead.Seal(nil, nonce, pt, ad)
+		useCLMUL = true
+		return bytes.Equal(sealedCLMUL, sealedGeneric)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkMul128Generic(b *testing.B) {
+	x := make([]byte, gost3412128.BlockSize)
+	y := make([]byte, gost3412128.BlockSize)
+	rand.Read(x)
+	rand.Read(y)
+	mul := newMul128()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mul.mulGeneric(x, y)
+	}
+}
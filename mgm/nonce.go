@@ -0,0 +1,100 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Number of nonces reserved by each NonceSequencer.MarshalState call.
+const NonceReserve = 1 << 20
+
+// Generator of monotonically increasing MGM nonces that survives
+// restarts. Nonce is big-endian counter, padded with zeros to the
+// nonce size, so its first bit is always zero, as MGM requires.
+//
+// Nonces are issued only within the reservation, made by
+// MarshalState: its result has to be persisted before the nonces are
+// used. State tells that all nonces before it may be already used, so
+// restored sequencer skips all of them, even if the crash happened
+// just after the reservation was made.
+type NonceSequencer struct {
+	nonceSize int
+	ctr       uint64
+	limit     uint64
+	max       uint64
+}
+
+// Create sequencer for MGM with nonceSize (cipher's block size) nonces.
+// MarshalState must be called before the first Next.
+func NewNonceSequencer(nonceSize int) (*NonceSequencer, error) {
+	s := NonceSequencer{nonceSize: nonceSize}
+	switch nonceSize {
+	case 8:
+		s.max = 1<<63 - 1
+	case 16:
+		s.max = 1<<64 - 1
+	default:
+		return nil, errors.New("gogost/mgm: only {8|16} nonce sizes allowed")
+	}
+	return &s, nil
+}
+
+// Get the next nonce. It panics if reserved nonces are exhausted:
+// MarshalState has to be called and persisted beforehand.
+func (s *NonceSequencer) Next() []byte {
+	if s.ctr >= s.limit {
+		panic("gogost/mgm: nonce reservation is exhausted, call MarshalState")
+	}
+	nonce := make([]byte, s.nonceSize)
+	binary.BigEndian.PutUint64(nonce[s.nonceSize-8:], s.ctr)
+	s.ctr++
+	return nonce
+}
+
+// Reserve next NonceReserve nonces and return the state to persist:
+// BE64 of the first nonce counter beyond the reservation.
+func (s *NonceSequencer) MarshalState() []byte {
+	limit := s.ctr + NonceReserve
+	if limit < s.ctr || limit > s.max {
+		limit = s.max
+	}
+	s.limit = limit
+	state := make([]byte, 8)
+	binary.BigEndian.PutUint64(state, limit)
+	return state
+}
+
+// Restore sequencer from persisted state: nonces continue from the
+// state's counter, all reserved before it are never issued again.
+// MarshalState must be called before the next Next.
+func (s *NonceSequencer) RestoreState(state []byte) error {
+	if len(state) != 8 {
+		return fmt.Errorf("gogost/mgm: len(state)=%d != 8", len(state))
+	}
+	ctr := binary.BigEndian.Uint64(state)
+	if ctr > s.max {
+		return errors.New("gogost/mgm: state counter is out of range")
+	}
+	if ctr < s.ctr {
+		return errors.New("gogost/mgm: state goes backwards")
+	}
+	s.ctr = ctr
+	s.limit = ctr
+	return nil
+}
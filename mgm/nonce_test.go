@@ -0,0 +1,103 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestNonceSequencerRestore(t *testing.T) {
+	seen := make(map[string]struct{})
+	use := func(s *NonceSequencer, n int) {
+		for i := 0; i < n; i++ {
+			nonce := s.Next()
+			if len(nonce) != 16 || nonce[0]&0x80 != 0 {
+				t.Fatal("invalid nonce")
+			}
+			if _, exists := seen[string(nonce)]; exists {
+				t.Fatal("nonce repeated")
+			}
+			seen[string(nonce)] = struct{}{}
+		}
+	}
+	s, err := NewNonceSequencer(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	persisted := s.MarshalState()
+	use(s, 1000)
+	for _, crashAfter := range []int{0, 1, 10} {
+		// Crash: in-memory state is lost, only persisted one survives
+		s, _ = NewNonceSequencer(16)
+		if err = s.RestoreState(persisted); err != nil {
+			t.Fatal(err)
+		}
+		persisted = s.MarshalState()
+		use(s, crashAfter)
+	}
+	use(s, 10)
+	last := s.Next()
+	if binary.BigEndian.Uint64(last[8:]) != 3*NonceReserve+20 {
+		t.FailNow()
+	}
+	if err = s.RestoreState(make([]byte, 8)); err == nil {
+		t.FailNow()
+	}
+	if len(seen) != 1000+0+1+10+10 {
+		t.FailNow()
+	}
+}
+
+func TestNonceSequencerReservation(t *testing.T) {
+	s, err := NewNonceSequencer(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.FailNow()
+			}
+		}()
+		s.Next()
+	}()
+	state := make([]byte, 8)
+	binary.BigEndian.PutUint64(state, 1<<63-2)
+	if err = s.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+	s.MarshalState()
+	if !bytes.Equal(s.Next(), []byte{0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFE}) {
+		t.FailNow()
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.FailNow()
+			}
+		}()
+		s.Next()
+	}()
+	binary.BigEndian.PutUint64(state, 1<<63)
+	if err = s.RestoreState(state); err == nil {
+		t.FailNow()
+	}
+	if _, err = NewNonceSequencer(12); err == nil {
+		t.FailNow()
+	}
+}
@@ -0,0 +1,105 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/hitchpock/gogost/v5/gost3412128"
+	"github.com/hitchpock/gogost/v5/gost3413"
+)
+
+// Size of the synthetic nonce prepended to SealSIV output.
+const SIVNonceSize = gost3412128.BlockSize
+
+// Nonce for SIV construction: Kuznyechik OMAC under macKey over
+// BE64(len(aad))||aad||plaintext with the higher bit cleared, as MGM
+// requires.
+func sivNonce(macKey, aad, plaintext []byte) []byte {
+	mac, err := gost3413.NewMAC(gost3412128.NewCipher(macKey), gost3412128.BlockSize)
+	if err != nil {
+		panic(err)
+	}
+	l := make([]byte, 8)
+	binary.BigEndian.PutUint64(l, uint64(len(aad)))
+	mac.Write(l)
+	mac.Write(aad)
+	mac.Write(plaintext)
+	nonce := mac.Sum(nil)
+	nonce[0] &= 0x7F
+	return nonce
+}
+
+func sivCheckKeys(encKey, macKey []byte) error {
+	if len(encKey) != gost3412128.KeySize || len(macKey) != gost3412128.KeySize {
+		return fmt.Errorf("keys must be %d bytes long", gost3412128.KeySize)
+	}
+	if hmac.Equal(encKey, macKey) {
+		return errors.New("encryption and MAC keys must differ")
+	}
+	return nil
+}
+
+// Deterministic Kuznyechik-MGM encryption, SIV-style: MGM nonce is
+// derived from aad and plaintext under the separate macKey, so no
+// nonce has to be generated. Identical (aad, plaintext) result in
+// identical output, revealing only the fact of equality. The result is
+// SIVNonceSize nonce, followed by ciphertext with the full tag.
+func SealSIV(encKey, macKey, aad, plaintext []byte) ([]byte, error) {
+	if err := sivCheckKeys(encKey, macKey); err != nil {
+		return nil, fmt.Errorf("gogost/mgm.SealSIV: %w", err)
+	}
+	if len(aad) == 0 && len(plaintext) == 0 {
+		return nil, errors.New("gogost/mgm.SealSIV: either aad or plaintext must be provided")
+	}
+	aead, err := NewMGM(gost3412128.NewCipher(encKey), gost3412128.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/mgm.SealSIV: %w", err)
+	}
+	nonce := sivNonce(macKey, aad, plaintext)
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Authenticate and decrypt SealSIV output. Besides MGM's tag, the
+// nonce is recomputed over the decrypted plaintext and must match.
+// InvalidTag is returned if either check fails.
+func OpenSIV(encKey, macKey, aad, sealed []byte) ([]byte, error) {
+	if err := sivCheckKeys(encKey, macKey); err != nil {
+		return nil, fmt.Errorf("gogost/mgm.OpenSIV: %w", err)
+	}
+	if len(sealed) < SIVNonceSize+gost3412128.BlockSize {
+		return nil, errors.New("gogost/mgm.OpenSIV: sealed data is too short")
+	}
+	nonce, ct := sealed[:SIVNonceSize], sealed[SIVNonceSize:]
+	if nonce[0]&0x80 > 0 {
+		return nil, InvalidTag
+	}
+	aead, err := NewMGM(gost3412128.NewCipher(encKey), gost3412128.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("gogost/mgm.OpenSIV: %w", err)
+	}
+	plaintext, err := aead.Open(nil, nonce, ct, aad)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(sivNonce(macKey, aad, plaintext), nonce) {
+		return nil, InvalidTag
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,67 @@
+// GoGOST -- Pure Go GOST cryptographic functions library
+// Copyright (C) 2015-2023 Sergey Matveev <stargrave@stargrave.org>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, version 3 of the License.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package mgm
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/quick"
+)
+
+func TestSIV(t *testing.T) {
+	f := func(encKey, macKey [32]byte, aad, plaintext []byte) bool {
+		if encKey == macKey || (len(aad) == 0 && len(plaintext) == 0) {
+			return true
+		}
+		sealed, err := SealSIV(encKey[:], macKey[:], aad, plaintext)
+		if err != nil {
+			return false
+		}
+		again, err := SealSIV(encKey[:], macKey[:], aad, plaintext)
+		if err != nil || !bytes.Equal(sealed, again) {
+			return false
+		}
+		opened, err := OpenSIV(encKey[:], macKey[:], aad, sealed)
+		if err != nil || !bytes.Equal(opened, plaintext) {
+			return false
+		}
+		other, err := SealSIV(encKey[:], macKey[:], append(aad, 0), plaintext)
+		if err != nil || bytes.Equal(other[:SIVNonceSize], sealed[:SIVNonceSize]) {
+			return false
+		}
+		sealed[len(sealed)-1] ^= 1
+		_, err = OpenSIV(encKey[:], macKey[:], aad, sealed)
+		return errors.Is(err, InvalidTag)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSIVInvalid(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := SealSIV(key, key, nil, []byte("data")); err == nil {
+		t.Fatal("equal keys accepted")
+	}
+	macKey := bytes.Repeat([]byte{1}, 32)
+	if _, err := SealSIV(key, macKey, nil, nil); err == nil {
+		t.Fatal("empty input accepted")
+	}
+	if _, err := OpenSIV(key, macKey, nil, make([]byte, SIVNonceSize)); err == nil {
+		t.Fatal("short input accepted")
+	}
+}